@@ -0,0 +1,88 @@
+package evalue
+
+import (
+	"math"
+)
+
+// An EProcess computes e-values incrementally, as observations of the two groups arrive.
+type EProcess interface {
+	// Push adds the observation v to a group, which must be either 1 or 2.
+	Push(group int, v float64)
+	// EValue returns the e-value of the observations so far.
+	EValue() float64
+}
+
+var (
+	_ EProcess = (*MomStream)(nil)
+	_ EProcess = (*AdaptiveMom)(nil)
+)
+
+// Constants of the plug-in effect size of AdaptiveMom.
+const (
+	// adaptivePriorDelta is the effect size before any evidence is observed.
+	adaptivePriorDelta = 0.5
+	// adaptivePriorWeight is the number of effective observations that adaptivePriorDelta is worth, which damps the noisy estimates of small samples.
+	adaptivePriorWeight = 4
+	// adaptiveMinDelta and adaptiveMaxDelta bound the plug-in effect size.
+	adaptiveMinDelta, adaptiveMaxDelta = 0.05, 3
+)
+
+// An AdaptiveMom is a mom e-process whose minimal effect size is not fixed in advance, but is estimated from the observations seen so far.
+// Each observation multiplies the e-value by the likelihood ratio increment of the mom e-process tuned to the effect size estimated before that observation.
+// Since the tuning is predictable, that is it depends only on past observations, the product is still an e-process.
+// It is often more powerful than NewMom with a misspecified minimal effect size, at the cost of some power when the minimal effect size is known.
+type AdaptiveMom struct {
+	p      *Mom
+	stream *MomStream
+	// logE is the log of the e-value.
+	logE float64
+}
+
+// NewAdaptiveMom creates an adaptive mom e-process.
+func NewAdaptiveMom() *AdaptiveMom {
+	p := NewMom(adaptivePriorDelta)
+	a := &AdaptiveMom{p: p, stream: NewMomStream(p)}
+	return a
+}
+
+// Push implements the EProcess interface.
+func (a *AdaptiveMom) Push(group int, v float64) {
+	prev := a.stream.EValue()
+	a.stream.Push(group, v)
+	a.logE += math.Log(a.stream.EValue()) - math.Log(prev)
+
+	// Tune the mom e-process for the next observation, by shrinking the observed effect size towards the prior one.
+	t := a.stream.TStat()
+	delta2 := adaptivePriorDelta * adaptivePriorDelta
+	if t.sufficient() && t.Sp > 0 {
+		d := (t.Mean1 - t.Mean2) / t.Sp
+		delta2 = (t.NEff*d*d + adaptivePriorWeight*delta2) / (t.NEff + adaptivePriorWeight)
+	}
+	delta := min(max(math.Sqrt(delta2), adaptiveMinDelta), adaptiveMaxDelta)
+	a.p.G = NewMom(delta).G
+}
+
+// EValue implements the EProcess interface.
+func (a *AdaptiveMom) EValue() float64 {
+	return math.Exp(a.logE)
+}
+
+// MarginalEGain returns the expected increase in the log e-value of the two sample data from one more observation in group 1, gain1, and in group 2, gain2.
+// The expectation is under the observed effect size d = (Mean1-Mean2)/Sp, with the t-statistic approximated by its mean sqrt(nEff)*d at the current and the next group sizes.
+// In a response-adaptive design, the next observation is best allocated to the group with the larger gain, which is typically the smaller group, since it increases the effective sample size the most.
+// The gains are negative if the observed effect is too small for one more observation to strengthen the evidence,
+// and 0 if there are too few observations to estimate the variance or both groups are constant, whose effect size is undefined, like in EValuePhi0.
+func (p *Mom) MarginalEGain(x, y []float64) (gain1, gain2 float64) {
+	ts := TStat(x, y, 0)
+	if !ts.sufficient() || ts.Sp == 0 {
+		return 0, 0
+	}
+	d := (ts.Mean1 - ts.Mean2) / ts.Sp
+	logE := func(n1, n2 int) float64 {
+		nEff := EffectiveSampleSize(n1, n2)
+		return p.logEValue(math.Sqrt(nEff)*d, float64(n1+n2-2), nEff)
+	}
+	n1, n2 := len(x), len(y)
+	current := logE(n1, n2)
+	return logE(n1+1, n2) - current, logE(n1, n2+1) - current
+}
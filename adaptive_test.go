@@ -0,0 +1,107 @@
+package evalue
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestAdaptiveMom(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	const numSims = 500
+	const n = 100
+	// rejectRate returns the fraction of simulated experiments whose e-process ever exceeds 1/alpha within n observations per group.
+	rejectRate := func(delta float64, newEProcess func() EProcess) float64 {
+		rnd := rand.New(newDefaultRandSource())
+		var rejected int
+		for range numSims {
+			x, y := GaussianGen{Delta: delta}.Generate(rnd, n)
+			ep := newEProcess()
+			for i := range n {
+				ep.Push(1, x[i])
+				ep.Push(2, y[i])
+				if ep.EValue() > 1./alpha {
+					rejected++
+					break
+				}
+			}
+		}
+		return float64(rejected) / numSims
+	}
+	adaptive := func() EProcess { return NewAdaptiveMom() }
+	misspecified := func() EProcess { return NewMomStream(NewMom(0.05)) }
+
+	tests := []struct {
+		delta        float64
+		adaptive     float64
+		misspecified float64
+	}{
+		{delta: 0, adaptive: 0.022, misspecified: 0},
+		{delta: 0.8, adaptive: 0.996, misspecified: 0},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			a, m := rejectRate(test.delta, adaptive), rejectRate(test.delta, misspecified)
+			if a != test.adaptive || m != test.misspecified {
+				t.Errorf("got %f %f want %f %f", a, m, test.adaptive, test.misspecified)
+			}
+			if test.delta == 0 && !(a <= alpha) {
+				t.Errorf("Type I error %f exceeds %f", a, alpha)
+			}
+			if test.delta != 0 && !(a > m) {
+				t.Errorf("adaptive power %f does not exceed %f", a, m)
+			}
+		})
+	}
+}
+
+func TestMarginalEGain(t *testing.T) {
+	t.Parallel()
+	p := NewMom(0.5)
+	tests := []struct {
+		delta float64
+		// smallerLarger is the fraction of simulations in which the smaller group has the larger gain.
+		smallerLarger float64
+	}{
+		{delta: 0.5, smallerLarger: 0.695},
+		{delta: 1, smallerLarger: 0.97},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			const numSimulations = 200
+			rnd := rand.New(rand.NewPCG(uint64(i), 1))
+			var smallerLarger float64
+			for range numSimulations {
+				x, _ := GaussianGen{Delta: test.delta}.Generate(rnd, 10)
+				_, y := GaussianGen{Delta: test.delta}.Generate(rnd, 30)
+				gain1, gain2 := p.MarginalEGain(x, y)
+				if gain1 > gain2 {
+					smallerLarger++
+				}
+
+				// Swapping the groups swaps the gains.
+				if swapped2, swapped1 := p.MarginalEGain(y, x); swapped1 != gain1 || swapped2 != gain2 {
+					t.Fatalf("swapped gains %f %f differ from %f %f", swapped1, swapped2, gain1, gain2)
+				}
+			}
+			if f := smallerLarger / numSimulations; f != test.smallerLarger {
+				t.Errorf("unexpected fraction of larger gains of the smaller group: got %f want %f", f, test.smallerLarger)
+			}
+			if !(smallerLarger/numSimulations > 0.5) {
+				t.Errorf("the smaller group does not typically have the larger gain")
+			}
+		})
+	}
+
+	if gain1, gain2 := p.MarginalEGain([]float64{1}, []float64{2}); gain1 != 0 || gain2 != 0 {
+		t.Errorf("unexpected gains without a variance estimate: %f %f", gain1, gain2)
+	}
+	for _, y := range [][]float64{{3, 3}, {5, 5}} {
+		if gain1, gain2 := p.MarginalEGain([]float64{3, 3, 3}, y); gain1 != 0 || gain2 != 0 {
+			t.Errorf("unexpected gains of constant groups %v: %f %f", y, gain1, gain2)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package evalue
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Methods reported by AutoEValue.
+const (
+	MethodT     = "t"
+	MethodWelch = "welch"
+	MethodRank  = "rank"
+)
+
+const (
+	// autoNormalityLevel is the significance level of the normality test in AutoEValue.
+	autoNormalityLevel = 0.01
+	// autoVarianceRatio is the ratio between the group variances above which AutoEValue abandons the pooled variance.
+	autoVarianceRatio = 2
+)
+
+// AutoEValue returns the e-value of the two sample data, using a method chosen by inspecting the data.
+// If the Jarque-Bera test rejects the normality of either group, the e-value is EValueRankSum, see MethodRank.
+// Otherwise, if the variance of one group is more than twice that of the other, the e-value is computed from Welch's t-statistic, see MethodWelch.
+// Otherwise, the e-value is that of EValue, see MethodT.
+//
+// Like EValue, every method returns 1 if there are too few observations to estimate the variance.
+//
+// Only MethodT retains the exact Type I error guarantee of e-values, the other methods are approximations that are robust to violations of the assumptions of the t-test.
+// Since the method depends on the data, callers should fix it once it is chosen early in an experiment.
+func AutoEValue(x, y []float64, deltaMin float64) (e float64, method string) {
+	p := NewMom(deltaMin)
+	switch method := AssumptionReport(x, y).Method; method {
+	case MethodRank:
+		return p.EValueRankSum(x, y), method
+	case MethodWelch:
+		return p.EValueFromTStat(tStatWelch(x, y)), method
+	default:
+		return p.EValue(x, y), method
+	}
+}
+
+// A Report diagnoses whether the two sample data violate the assumptions of EValue, which are Gaussian data with equal variances.
+type Report struct {
+	// Skew is the skewness of each group.
+	Skew [2]float64
+	// ExKurtosis is the excess kurtosis of each group.
+	ExKurtosis [2]float64
+	// NormalityPValue is the p-value of the Jarque-Bera test of the normality of each group.
+	// It is 1 for groups with fewer than 8 observations, for which the test is meaningless.
+	NormalityPValue [2]float64
+	// VarianceRatio is the ratio of the larger group variance to the smaller one.
+	VarianceRatio float64
+
+	// NonNormal reports whether the normality of either group is rejected.
+	NonNormal bool
+	// UnequalVariance reports whether VarianceRatio is so large that the pooled variance is inappropriate.
+	UnequalVariance bool
+	// Method is the suggested method, which is that chosen by AutoEValue.
+	Method string
+}
+
+// AssumptionReport checks whether the two sample data violate the assumptions of EValue, and suggests the Welch or rank variants accordingly.
+// The checks and their thresholds are those of AutoEValue.
+func AssumptionReport(x, y []float64) Report {
+	var r Report
+	for i, g := range [2][]float64{x, y} {
+		r.Skew[i], r.ExKurtosis[i] = stat.Skew(g, nil), stat.ExKurtosis(g, nil)
+		r.NormalityPValue[i] = jarqueBera(g)
+		if r.NormalityPValue[i] < autoNormalityLevel {
+			r.NonNormal = true
+		}
+	}
+	vx, vy := stat.Variance(x, nil), stat.Variance(y, nil)
+	r.VarianceRatio = max(vx, vy) / min(vx, vy)
+	r.UnequalVariance = r.VarianceRatio > autoVarianceRatio
+
+	switch {
+	case r.NonNormal:
+		r.Method = MethodRank
+	case r.UnequalVariance:
+		r.Method = MethodWelch
+	default:
+		r.Method = MethodT
+	}
+	return r
+}
+
+// jarqueBera returns the p-value of the Jarque-Bera test of the normality of x, which is 1 for fewer than 8 observations.
+func jarqueBera(x []float64) float64 {
+	// The test is meaningless for tiny samples.
+	if len(x) < 8 {
+		return 1
+	}
+	n := float64(len(x))
+	s, k := stat.Skew(x, nil), stat.ExKurtosis(x, nil)
+	jb := n / 6 * (s*s + k*k/4)
+	return 1 - distuv.ChiSquared{K: 2}.CDF(jb)
+}
+
+// tStatWelch returns the two sample t-statistic with Welch's unequal variances.
+// The degree of freedom is given by the Welch-Satterthwaite equation.
+func tStatWelch(x1, x2 []float64) TStatistic {
+	n1, n2 := float64(len(x1)), float64(len(x2))
+	mean1, v1 := stat.MeanVariance(x1, nil)
+	mean2, v2 := stat.MeanVariance(x2, nil)
+	a1, a2 := v1/n1, v2/n2
+	nu := (a1 + a2) * (a1 + a2) / (a1*a1/(n1-1) + a2*a2/(n2-1))
+	nEff := n1 * n2 / (n1 + n2)
+
+	// Sp is chosen such that T = sqrt(NEff)*(Mean1-Mean2)/Sp.
+	sp := math.Sqrt(nEff * (a1 + a2))
+	t := (mean1 - mean2) / math.Sqrt(a1+a2)
+
+	ts := TStatistic{
+		Nu:    nu,
+		NEff:  nEff,
+		Mean1: mean1,
+		Mean2: mean2,
+		Sp:    sp,
+		T:     t,
+	}
+	return ts
+}
@@ -0,0 +1,77 @@
+package evalue
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestAutoEValue(t *testing.T) {
+	t.Parallel()
+	rsrc := rand.NewChaCha8([32]byte{0xb2, 0x11, 0x8a, 0x08, 0x83, 0x15, 0x07, 0x19, 0x64, 0x7a, 0x64, 0x5f, 0x71, 0x7e, 0x07, 0x01, 0xd9, 0x80, 0x61, 0xed, 0xce, 0xaa, 0x4e, 0xf2, 0x2f, 0x36, 0xb5, 0x18, 0x82, 0x85, 0x1c, 0x24})
+	sample := func(dist interface{ Rand() float64 }, n int) []float64 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = dist.Rand()
+		}
+		return x
+	}
+	const n = 100
+	tests := []struct {
+		x      []float64
+		y      []float64
+		method string
+	}{
+		{x: sample(distuv.Normal{Mu: 0.5, Sigma: 1, Src: rsrc}, n), y: sample(distuv.Normal{Sigma: 1, Src: rsrc}, n), method: MethodT},
+		{x: sample(distuv.Normal{Mu: 0.5, Sigma: 3, Src: rsrc}, n), y: sample(distuv.Normal{Sigma: 1, Src: rsrc}, n), method: MethodWelch},
+		{x: sample(distuv.StudentsT{Mu: 0.5, Sigma: 1, Nu: 1, Src: rsrc}, n), y: sample(distuv.StudentsT{Sigma: 1, Nu: 1, Src: rsrc}, n), method: MethodRank},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			e, method := AutoEValue(test.x, test.y, 0.5)
+			if method != test.method {
+				t.Errorf("unexpected method: got %s want %s", method, test.method)
+			}
+			if !(e > 0) {
+				t.Errorf("invalid e-value %f", e)
+			}
+		})
+	}
+
+	// Single observations carry no evidence, whose variance is undefined.
+	if e, method := AutoEValue([]float64{1}, []float64{2}, 0.5); e != 1 {
+		t.Errorf("unexpected e-value of single observations by %s: got %f want 1", method, e)
+	}
+	if e := NewMom(0.5).EValueFromTStat(tStatWelch([]float64{1}, []float64{1, 5, 9})); e != 1 {
+		t.Errorf("unexpected Welch e-value of a single observation: got %f want 1", e)
+	}
+}
+
+func TestAssumptionReport(t *testing.T) {
+	t.Parallel()
+	// The Likert scale of the Gray data is bounded and discrete, and so not Gaussian.
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	r := AssumptionReport(x, y)
+	if !r.NonNormal || r.Method != MethodRank {
+		t.Errorf("non-normality is not flagged: %+v", r)
+	}
+	if _, method := AutoEValue(x, y, 0.5); method != r.Method {
+		t.Errorf("got method %s want %s", r.Method, method)
+	}
+
+	// Gaussian data pass the checks.
+	rnd := rand.New(newDefaultRandSource())
+	x, y = GaussianGen{Delta: 0.5}.Generate(rnd, 100)
+	r = AssumptionReport(x, y)
+	if r.NonNormal || r.UnequalVariance || r.Method != MethodT {
+		t.Errorf("Gaussian data are flagged: %+v", r)
+	}
+	if !(r.VarianceRatio >= 1 && r.NormalityPValue[0] > 0.01 && r.NormalityPValue[1] > 0.01) {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
@@ -0,0 +1,54 @@
+package evalue
+
+// A BatchSequentialTest performs an e-value based two sample test with optional stopping, where observations arrive in batches.
+// The e-value is evaluated once per batch, which matches data collection cadences such as daily or weekly reports.
+type BatchSequentialTest struct {
+	alpha  float64
+	stream *MomStream
+	stopT  int
+}
+
+// NewBatchSequentialTest creates a batch sequential test of the mom e-process p at the significance level alpha.
+// alpha must lie in (0, 1), otherwise the test never rejects the null hypothesis.
+func NewBatchSequentialTest(p *Mom, alpha float64) *BatchSequentialTest {
+	bt := &BatchSequentialTest{
+		alpha:  alpha,
+		stream: NewMomStream(p),
+		stopT:  notStopped,
+	}
+	return bt
+}
+
+// AddBatch adds a batch of observations g1 of group 1 and g2 of group 2, and reports whether the null hypothesis is rejected.
+// Once the null hypothesis is rejected, the test stops and ignores further batches.
+func (bt *BatchSequentialTest) AddBatch(g1, g2 []float64) bool {
+	if bt.Stopped() {
+		return true
+	}
+	for _, v := range g1 {
+		bt.stream.Push(1, v)
+	}
+	for _, v := range g2 {
+		bt.stream.Push(2, v)
+	}
+	if validProbability(bt.alpha) && bt.stream.EValue() > 1./bt.alpha {
+		bt.stopT = bt.stream.n[0] + bt.stream.n[1]
+		return true
+	}
+	return false
+}
+
+// Stopped reports whether the null hypothesis is rejected.
+func (bt *BatchSequentialTest) Stopped() bool {
+	return bt.stopT != notStopped
+}
+
+// StopT returns the number of observations, summed over both groups, at which the null hypothesis is rejected, or -1 if it is not rejected.
+func (bt *BatchSequentialTest) StopT() int {
+	return bt.stopT
+}
+
+// EValue returns the e-value at the end of the last batch, which is frozen at the stopping time.
+func (bt *BatchSequentialTest) EValue() float64 {
+	return bt.stream.EValue()
+}
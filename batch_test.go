@@ -0,0 +1,43 @@
+package evalue
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestBatchSequentialTest(t *testing.T) {
+	t.Parallel()
+	// The same data as TestOptionalContinuation.
+	rsrc := rand.NewChaCha8([32]byte{0xb2, 0x11, 0x8a, 0x08, 0x83, 0x15, 0x07, 0x19, 0x64, 0x7a, 0x64, 0x5f, 0x71, 0x7e, 0x07, 0x01, 0xd9, 0x80, 0x61, 0xed, 0xce, 0xaa, 0x4e, 0xf2, 0x2f, 0x36, 0xb5, 0x18, 0x82, 0x85, 0x1c, 0x24})
+	const alpha = 0.05
+	const numSamples = 1e3
+	const numBatches = 5
+	const batchSize = 40
+	rawData := normData(rsrc, 0, numSamples, numBatches*batchSize)
+
+	p := NewMom(0.51765)
+	var stopped int
+	for _, sample := range rawData {
+		x, y := sample[0], sample[1]
+		bt := NewBatchSequentialTest(p, alpha)
+		wantStopT := notStopped
+		for batch := range numBatches {
+			lo, hi := batch*batchSize, (batch+1)*batchSize
+			bt.AddBatch(x[lo:hi], y[lo:hi])
+			if wantStopT == notStopped && p.EValue(x[:hi], y[:hi]) > 1./alpha {
+				wantStopT = 2 * hi
+			}
+		}
+		if bt.StopT() != wantStopT {
+			t.Errorf("unexpected stopping time: got %d want %d", bt.StopT(), wantStopT)
+		}
+		if bt.Stopped() {
+			stopped++
+		}
+	}
+
+	// The type I error of the e-value with optional continuation in TestOptionalContinuation.
+	if typeI := float64(stopped) / numSamples; typeI != 0.012 {
+		t.Errorf("unexpected type I error: got %f want %f", typeI, 0.012)
+	}
+}
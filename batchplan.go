@@ -0,0 +1,40 @@
+package evalue
+
+import (
+	"math"
+)
+
+// A BatchPlan is the planned sample size of an experiment without early stopping, where the e-value is evaluated only once at the end.
+// It parallels NPlan, whose Batch is N1.
+type BatchPlan struct {
+	// N1 and N2 are the sizes of the two groups, which are -1 if the desired power cannot be reached.
+	N1, N2 int
+	// Noncentrality is the noncentrality parameter of the t-statistic at the planned sizes, when the true effect size is deltaMin.
+	Noncentrality float64
+	// CriticalT is the critical t at the planned sizes, beyond which the e-value exceeds 1/alpha.
+	CriticalT float64
+	// Power is the probability that the e-value exceeds 1/alpha at the planned sizes, which is at least 1-beta.
+	Power float64
+}
+
+// GetBatchPlan returns the planned sample size of an experiment without early stopping.
+// The arguments are those of GetNPlanErr, of which only the options Ratio, N2 and Mom are used.
+func GetBatchPlan(alpha, beta, deltaMin float64, options ...GetNPlanOptions) (BatchPlan, error) {
+	opt, err := newGetNPlanOptions(alpha, beta, deltaMin, options)
+	if err != nil {
+		return BatchPlan{}, err
+	}
+	n1, n2 := batchSizes(alpha, beta, deltaMin, opt)
+	bp := BatchPlan{N1: n1, N2: n2, Noncentrality: math.NaN(), CriticalT: math.NaN(), Power: math.NaN()}
+	if n1 < 0 {
+		return bp, nil
+	}
+
+	nu, nEff := float64(n1+n2-2), EffectiveSampleSize(n1, n2)
+	bp.Noncentrality = Noncentrality(nEff, math.Abs(deltaMin))
+	bp.CriticalT = opt.Mom.CriticalT(nu, nEff, alpha)
+	// The e-value test rejects when |t| exceeds the critical t.
+	t := NoncentralT{Nu: nu, Mu: bp.Noncentrality}
+	bp.Power = t.SurvivalFunction(bp.CriticalT) + t.CDF(-bp.CriticalT)
+	return bp, nil
+}
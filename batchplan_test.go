@@ -0,0 +1,54 @@
+package evalue
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestGetBatchPlan(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alpha    float64
+		beta     float64
+		deltaMin float64
+		options  GetNPlanOptions
+	}{
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{Ratio: 2}},
+		{alpha: 0.01, beta: 0.1, deltaMin: 0.3, options: GetNPlanOptions{N2: 1000}},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			bp, err := GetBatchPlan(test.alpha, test.beta, test.deltaMin, test.options)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			opt := test.options
+			opt.NumSimulations = 1
+			if batch := GetNPlan(test.alpha, test.beta, test.deltaMin, opt).Batch; bp.N1 != batch {
+				t.Errorf("got N1 %d want %d", bp.N1, batch)
+			}
+
+			nu, nEff := float64(bp.N1+bp.N2-2), EffectiveSampleSize(bp.N1, bp.N2)
+			if mu := math.Sqrt(nEff) * test.deltaMin; !scalar.EqualWithinRel(bp.Noncentrality, mu, 1e-12) {
+				t.Errorf("got noncentrality %f want %f", bp.Noncentrality, mu)
+			}
+			if c := NewMom(test.deltaMin).CriticalT(nu, nEff, test.alpha); bp.CriticalT != c {
+				t.Errorf("got critical t %f want %f", bp.CriticalT, c)
+			}
+			// Rounding up the sizes yields slightly more than the desired power.
+			if !(bp.Power >= 1-test.beta && bp.Power < 1-test.beta+0.01) {
+				t.Errorf("unexpected power %f", bp.Power)
+			}
+		})
+	}
+
+	if _, err := GetBatchPlan(0.05, 1.2, 0.5); !errors.Is(err, ErrInvalidProbability) {
+		t.Errorf("unexpected error %+v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package evalue
+
+import (
+	"math/big"
+)
+
+// maxBigTerms bounds the number of series terms summed by eValueBig.
+const maxBigTerms = 1 << 20
+
+// eValueBig returns the e-value of a t-statistic, computed with big.Float arithmetic of prec mantissa bits.
+// The hypergeometric function is summed directly from its power series, whose terms are all positive, so no digits are lost to cancellation.
+// The result is only as accurate as the float64 inputs, and summing the series is slow when z is close to 1.
+func eValueBig(g, t, nu, nEff float64, prec uint) *big.Float {
+	newFloat := func(x float64) *big.Float { return new(big.Float).SetPrec(prec).SetFloat64(x) }
+	one := newFloat(1)
+
+	// ng = nEff*g, and the prefactor is (1+ng)^(-3/2).
+	ng := newFloat(nEff)
+	ng.Mul(ng, newFloat(g))
+	onePlusNG := newFloat(0).Add(one, ng)
+	prefactor := newFloat(0).Sqrt(onePlusNG)
+	prefactor.Mul(prefactor, onePlusNG)
+	prefactor.Quo(one, prefactor)
+
+	// z = t^2/(nu+t^2) * ng/(1+ng).
+	t2 := newFloat(t)
+	t2.Mul(t2, t2)
+	z := newFloat(0).Add(newFloat(nu), t2)
+	z.Quo(t2, z)
+	z.Mul(z, ng)
+	z.Quo(z, onePlusNG)
+
+	// Sum the hypergeometric series 2F1(a, b; c; z).
+	a, b, c := newFloat((nu+1)/2), newFloat(3./2), newFloat(1./2)
+	sum, term := newFloat(1), newFloat(1)
+	ratio := newFloat(0)
+	for k := range maxBigTerms {
+		kf := newFloat(float64(k))
+		ratio.Add(a, kf)
+		ratio.Mul(ratio, newFloat(0).Add(b, kf))
+		ratio.Quo(ratio, newFloat(0).Add(c, kf))
+		ratio.Quo(ratio, newFloat(float64(k+1)))
+		ratio.Mul(ratio, z)
+		term.Mul(term, ratio)
+		sum.Add(sum, term)
+
+		// Stop when the term no longer affects the sum.
+		// Since the terms eventually decrease geometrically, the remainder is negligible once the term is.
+		if k > 0 && term.MantExp(nil) < sum.MantExp(nil)-int(prec) {
+			break
+		}
+	}
+
+	return sum.Mul(sum, prefactor)
+}
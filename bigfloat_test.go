@@ -0,0 +1,49 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestEValueBig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		t       float64
+		n1      int
+		n2      int
+		diverge bool
+	}{
+		{t: 0, n1: 2, n2: 2},
+		{t: 2.244057, n1: 9, n2: 8},
+		{t: 5.976485, n1: 64, n2: 54},
+		{t: 10, n1: 1000, n2: 1000},
+		{t: 30, n1: 1000, n2: 1000},
+		// The float64 hypergeometric function overflows, and eValue falls back to the series in high precision.
+		{t: 60, n1: 10000, n2: 10000},
+		// Highly unbalanced groups, whose small nEff keeps the argument of the hypergeometric function far from 1 even for large t.
+		{t: 10, n1: 100000, n2: 2},
+		{t: 40, n1: 100000, n2: 2},
+		{t: 80, n1: 1000000, n2: 2},
+		{t: 80, n1: 100000, n2: 1},
+		{t: 1000, n1: 1000, n2: 3},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			n1, n2 := float64(test.n1), float64(test.n2)
+			nu := n1 + n2 - 2
+			nEff := n1 * n2 / (n1 + n2)
+			e := (&Mom{G: 0.1339827}).eValue(test.t, nu, nEff)
+			eBig := (&Mom{G: 0.1339827, Prec: 256}).eValue(test.t, nu, nEff)
+			if diverge := !scalar.EqualWithinRel(e, eBig, 1e-12); diverge != test.diverge {
+				t.Errorf("eValue(%f, %d, %d): float64 %g high precision %g, diverge %t want %t", test.t, test.n1, test.n2, e, eBig, diverge, test.diverge)
+			}
+			if math.IsNaN(eBig) {
+				t.Errorf("high precision eValue(%f, %d, %d) is NaN", test.t, test.n1, test.n2)
+			}
+		})
+	}
+}
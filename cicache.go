@@ -0,0 +1,36 @@
+package evalue
+
+// A CICache memoizes the critical t-statistics of a mom e-process, so that repeated confidence intervals along a sequential experiment are cheap.
+// A CICache is not safe for concurrent use.
+type CICache struct {
+	p     *Mom
+	cache map[ciCacheKey]float64
+}
+
+type ciCacheKey struct {
+	nu    float64
+	nEff  float64
+	alpha float64
+}
+
+// NewCICache creates a cache of the critical t-statistics of the mom e-process p.
+func NewCICache(p *Mom) *CICache {
+	return &CICache{p: p, cache: make(map[ciCacheKey]float64)}
+}
+
+// CriticalT returns the same result as Mom.CriticalT, solving for it only on the first call for the given arguments.
+func (c *CICache) CriticalT(nu, nEff, alpha float64) float64 {
+	key := ciCacheKey{nu: nu, nEff: nEff, alpha: alpha}
+	if tAlpha, ok := c.cache[key]; ok {
+		return tAlpha
+	}
+	tAlpha := c.p.CriticalT(nu, nEff, alpha)
+	c.cache[key] = tAlpha
+	return tAlpha
+}
+
+// CI returns the same result as Mom.CI, using the cached critical t-statistics.
+func (c *CICache) CI(x, y []float64, alpha float64) [2]float64 {
+	t := TStat(x, y, 0)
+	return ciOfT(t, c.CriticalT(t.Nu, t.NEff, alpha))
+}
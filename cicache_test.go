@@ -0,0 +1,36 @@
+package evalue
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCICache(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	const alpha = 0.05
+	p := &Mom{G: 0.1339827}
+	c := NewCICache(p)
+	// Go through the data twice, so that the second pass hits the cache.
+	for range 2 {
+		for n := 4; n <= len(data); n++ {
+			x, y := splitGray(data[:n])
+			// Intervals of data whose t-statistic is undefined are NaN.
+			sameFloat := func(a, b float64) bool { return a == b || (math.IsNaN(a) && math.IsNaN(b)) }
+			if ci, want := c.CI(x, y, alpha), p.CI(x, y, alpha); !slices.EqualFunc(ci[:], want[:], sameFloat) {
+				t.Errorf("unexpected cached CI(data[:%d]): got %v want %v", n, ci, want)
+			}
+		}
+	}
+}
+
+func BenchmarkCICache(b *testing.B) {
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	c := NewCICache(&Mom{G: 0.1339827})
+	b.ReportAllocs()
+	for b.Loop() {
+		c.CI(x, y, 0.05)
+	}
+}
@@ -0,0 +1,62 @@
+package evalue
+
+import (
+	"math"
+)
+
+// A CISequence computes the confidence intervals of a mom e-process incrementally, as observations of the two groups arrive.
+// Since the confidence intervals are anytime-valid, they form a confidence sequence which covers the true difference between the group means at all times simultaneously.
+type CISequence struct {
+	stream       *MomStream
+	alpha        float64
+	intersection bool
+	ci           [2]float64
+}
+
+// CISequenceOptions are options for NewCISequence.
+type CISequenceOptions struct {
+	// Intersection makes CI return the running intersection of the confidence intervals so far.
+	// The per-step confidence interval may widen transiently with noisy data, whereas the running intersection never widens.
+	// The running intersection is itself a valid confidence sequence, and is empty, that is CI()[0] > CI()[1], with probability at most alpha.
+	Intersection bool
+}
+
+// NewCISequence creates a confidence sequence of the mom e-process p at the significance level alpha.
+func NewCISequence(p *Mom, alpha float64, options ...CISequenceOptions) *CISequence {
+	var opt CISequenceOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	s := &CISequence{
+		stream:       NewMomStream(p),
+		alpha:        alpha,
+		intersection: opt.Intersection,
+		ci:           [2]float64{math.Inf(-1), math.Inf(1)},
+	}
+	return s
+}
+
+// Push adds the observation v to a group, which must be either 1 or 2.
+func (s *CISequence) Push(group int, v float64) {
+	s.stream.Push(group, v)
+	n := s.stream.n
+	if n[0] == 0 || n[1] == 0 || n[0]+n[1] <= 2 {
+		return
+	}
+	// Constant groups carry no evidence, like in MomStream.EValue, and would otherwise give a zero width interval.
+	if !s.stream.positiveVariance(0) && !s.stream.positiveVariance(1) {
+		return
+	}
+
+	t := s.stream.TStat()
+	ci := ciOfT(t, s.stream.p.CriticalT(t.Nu, t.NEff, s.alpha))
+	if s.intersection {
+		ci = [2]float64{max(ci[0], s.ci[0]), min(ci[1], s.ci[1])}
+	}
+	s.ci = ci
+}
+
+// CI returns the confidence interval of the observations so far.
+func (s *CISequence) CI() [2]float64 {
+	return s.ci
+}
@@ -0,0 +1,73 @@
+package evalue
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestCISequence(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	const alpha = 0.05
+	p := &Mom{G: 0.1339827}
+	seq := NewCISequence(p, alpha)
+	intersection := NewCISequence(p, alpha, CISequenceOptions{Intersection: true})
+	var widened bool
+	for n, d := range data {
+		group := 2
+		if d.factor == adultHarmsBaby {
+			group = 1
+		}
+		prev, prevIntersection := seq.CI(), intersection.CI()
+		seq.Push(group, float64(d.variable))
+		intersection.Push(group, float64(d.variable))
+
+		// The sequence agrees with CI.
+		x, y := splitGray(data[:n+1])
+		if len(x) > 1 && len(y) > 1 {
+			ci, want := seq.CI(), p.CI(x, y, alpha)
+			if !scalar.EqualWithinAbsOrRel(ci[0], want[0], 1e-9, 1e-9) || !scalar.EqualWithinAbsOrRel(ci[1], want[1], 1e-9, 1e-9) {
+				t.Errorf("unexpected CI at data[:%d]: got %v want %v", n+1, ci, want)
+			}
+		}
+		if seq.CI()[1]-seq.CI()[0] > prev[1]-prev[0] {
+			widened = true
+		}
+
+		// The intersection at step n is contained in that at step n-1.
+		if ci := intersection.CI(); !(prevIntersection[0] <= ci[0] && ci[1] <= prevIntersection[1]) {
+			t.Errorf("intersection at data[:%d] %v is not contained in %v", n+1, ci, prevIntersection)
+		}
+	}
+	if !widened {
+		t.Errorf("per-step CI never widens")
+	}
+}
+
+func TestCISequenceConstantStart(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	for _, intersection := range []bool{false, true} {
+		seq := NewCISequence(NewMom(0.5), alpha, CISequenceOptions{Intersection: intersection})
+		// Identical leading observations, as in Likert scale data, say nothing about the difference between the groups.
+		for range 20 {
+			seq.Push(1, 4)
+			seq.Push(2, 3)
+			if ci, want := seq.CI(), [2]float64{math.Inf(-1), math.Inf(1)}; ci != want {
+				t.Fatalf("intersection %t: unexpected CI of constant groups: got %v want %v", intersection, ci, want)
+			}
+		}
+
+		// Once the groups vary, the interval covers the difference between the group means.
+		for _, v := range []float64{5, 2, 4, 3} {
+			seq.Push(1, v+1)
+			seq.Push(2, v)
+		}
+		if ci := seq.CI(); !(ci[0] < 1 && 1 < ci[1] && !math.IsInf(ci[1]-ci[0], 1)) {
+			t.Errorf("intersection %t: unexpected CI %v", intersection, ci)
+		}
+	}
+}
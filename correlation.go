@@ -0,0 +1,49 @@
+package evalue
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// correlationRhoMin is the correlation at which EValueCorrelation rejects the null hypothesis at the fastest rate.
+// It is a medium association in the convention of Cohen.
+const correlationRhoMin = 0.3
+
+// EValueCorrelation returns the e-value of the paired data x and y, for the null hypothesis that their correlation rho is zero.
+// It is the e-value of Mom.EValueCorrelation, whose mom e-process is tuned to a correlation of 0.3.
+// To tune to another minimal correlation rhoMin, use NewMom(rhoMin/math.Sqrt(1-rhoMin*rhoMin)).EValueCorrelation instead.
+func EValueCorrelation(x, y []float64) float64 {
+	rho := correlationRhoMin
+	return NewMom(rho/math.Sqrt(1-rho*rho)).EValueCorrelation(x, y)
+}
+
+// EValueCorrelation returns the e-value of the paired data x and y, for the null hypothesis that they are not associated.
+// The association is measured by Spearman's rank correlation rs, whose t-statistic rs*sqrt((n-2)/(1-rs^2)) takes the place of the two sample t-statistic in EValue.
+// The effect size is then the standardized slope rho/sqrt(1-rho^2) of the ranks.
+//
+// Like EValueRankSum, the e-value relies on the asymptotic normality of the rank correlation, and so its Type I error guarantee holds only approximately.
+// It returns 1 for fewer than three pairs, and NaN if x and y differ in length.
+func (p *Mom) EValueCorrelation(x, y []float64) float64 {
+	if len(x) != len(y) {
+		return math.NaN()
+	}
+	n := float64(len(x))
+	if n < 3 {
+		return 1
+	}
+	rx, _ := ranks(x, nil)
+	ry, _ := ranks(y, nil)
+	rs := stat.Correlation(rx, ry, nil)
+	if math.IsNaN(rs) {
+		// Either x or y is constant, and so there is no evidence of association.
+		rs = 0
+	}
+	// Avoid an infinite t-statistic for perfectly correlated data.
+	rs = math.Max(-1+1e-12, math.Min(rs, 1-1e-12))
+	nu := n - 2
+	t := rs * math.Sqrt(nu/(1-rs*rs))
+	// The t-statistic of the slope has the noncentrality parameter delta*sqrt(n-1) for the standardized slope delta,
+	// since the squared deviations of the ranks rx from their mean sum to n-1 times their variance, and so the effective sample size is n-1.
+	return p.eValue(t, nu, n-1)
+}
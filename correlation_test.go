@@ -0,0 +1,69 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestEValueCorrelation(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	correlated := func(rnd *rand.Rand, rho float64, n int) ([]float64, []float64) {
+		x, y := make([]float64, n), make([]float64, n)
+		for i := range n {
+			x[i] = rnd.NormFloat64()
+			y[i] = rho*x[i] + math.Sqrt(1-rho*rho)*rnd.NormFloat64()
+		}
+		return x, y
+	}
+	tests := []struct {
+		eValue func(x, y []float64) float64
+	}{
+		{eValue: NewMom(0.5).EValueCorrelation},
+		{eValue: EValueCorrelation},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			// Independent x and y reject at most at the nominal rate, even when monitored continuously.
+			rnd := rand.New(newDefaultRandSource())
+			const numSamples = 1000
+			var rejected int
+			for range numSamples {
+				x, y := correlated(rnd, 0, 100)
+				for n := 3; n <= len(x); n++ {
+					if test.eValue(x[:n], y[:n]) > 1./alpha {
+						rejected++
+						break
+					}
+				}
+			}
+			if typeI := float64(rejected) / numSamples; !(typeI <= alpha) {
+				t.Errorf("Type I error %f exceeds alpha %f", typeI, alpha)
+			}
+
+			// The e-value of correlated data grows with the sample size.
+			x, y := correlated(rnd, 0.5, 200)
+			prev := test.eValue(x[:25], y[:25])
+			for _, n := range []int{50, 100, 200} {
+				e := test.eValue(x[:n], y[:n])
+				if !(e > prev) {
+					t.Errorf("e-value at %d is %f, not larger than %f", n, e, prev)
+				}
+				prev = e
+			}
+			if !(prev > 1./alpha) {
+				t.Errorf("correlated data not rejected: %f", prev)
+			}
+		})
+	}
+
+	if e := EValueCorrelation([]float64{1, 2, 3, 4}, []float64{1, 2, 3}); !math.IsNaN(e) {
+		t.Errorf("unexpected e-value of unpaired data: got %f want NaN", e)
+	}
+	if e := EValueCorrelation([]float64{1, 2}, []float64{2, 1}); e != 1 {
+		t.Errorf("unexpected e-value of two pairs: got %f want 1", e)
+	}
+}
@@ -0,0 +1,130 @@
+package evalue
+
+import (
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// A DataGen generates the data of the two groups in simulations.
+type DataGen interface {
+	// Generate returns n observations of each group.
+	Generate(rnd *rand.Rand, n int) (g1, g2 []float64)
+}
+
+// A DataGenInto is a DataGen that generates data into given slices.
+// Simulations reuse the slices across experiments, and so avoid allocating for each experiment.
+type DataGenInto interface {
+	DataGen
+	// GenerateInto fills g1 and g2, which must have the same length, with observations of each group.
+	GenerateInto(rnd *rand.Rand, g1, g2 []float64)
+}
+
+// generateInto fills g1 and g2 with the data of gen, without allocating if gen is a DataGenInto.
+func generateInto(gen DataGen, rnd *rand.Rand, g1, g2 []float64) {
+	if gen, ok := gen.(DataGenInto); ok {
+		gen.GenerateInto(rnd, g1, g2)
+		return
+	}
+	x, y := gen.Generate(rnd, len(g1))
+	copy(g1, x)
+	copy(g2, y)
+}
+
+// A SymmetricGen is a DataGen whose groups are symmetric about their locations.
+// Reflecting its data about the locations yields the antithetic data, which are equally likely.
+// Custom generators implement it to support GetNPlanOptions.Antithetic.
+type SymmetricGen interface {
+	DataGen
+	// Locations returns the locations of the two groups.
+	Locations() (float64, float64)
+}
+
+// antithetic reflects the data g1 and g2 of gen about their locations in place.
+func antithetic(gen SymmetricGen, g1, g2 []float64) {
+	c1, c2 := gen.Locations()
+	for i := range g1 {
+		g1[i] = 2*c1 - g1[i]
+	}
+	for i := range g2 {
+		g2[i] = 2*c2 - g2[i]
+	}
+}
+
+// A GaussianGen generates Gaussian data with unit variance, whose group means are Delta/2 and -Delta/2.
+type GaussianGen struct {
+	// Delta is the difference between the group means, which is also the effect size.
+	Delta float64
+}
+
+// Generate implements the DataGen interface.
+func (gen GaussianGen) Generate(rnd *rand.Rand, n int) ([]float64, []float64) {
+	g1, g2 := make([]float64, n), make([]float64, n)
+	gen.GenerateInto(rnd, g1, g2)
+	return g1, g2
+}
+
+// GenerateInto implements the DataGenInto interface.
+func (gen GaussianGen) GenerateInto(rnd *rand.Rand, g1, g2 []float64) {
+	for i := range g1 {
+		g1[i] = gen.Delta/2 + rnd.NormFloat64()
+		g2[i] = -gen.Delta/2 + rnd.NormFloat64()
+	}
+}
+
+// Locations implements the SymmetricGen interface.
+func (gen GaussianGen) Locations() (float64, float64) {
+	return gen.Delta / 2, -gen.Delta / 2
+}
+
+// A StudentTGen generates data following Student's t-distribution with unit scale, whose group locations are Delta/2 and -Delta/2.
+// It models heavy-tailed data which violate the normality assumption of the t-test.
+type StudentTGen struct {
+	// Delta is the difference between the group locations.
+	Delta float64
+	// Nu is the degree of freedom.
+	Nu float64
+}
+
+// Generate implements the DataGen interface.
+func (gen StudentTGen) Generate(rnd *rand.Rand, n int) ([]float64, []float64) {
+	g1, g2 := make([]float64, n), make([]float64, n)
+	gen.GenerateInto(rnd, g1, g2)
+	return g1, g2
+}
+
+// GenerateInto implements the DataGenInto interface.
+func (gen StudentTGen) GenerateInto(rnd *rand.Rand, g1, g2 []float64) {
+	dist := distuv.StudentsT{Sigma: 1, Nu: gen.Nu, Src: rnd}
+	for i := range g1 {
+		g1[i] = gen.Delta/2 + dist.Rand()
+		g2[i] = -gen.Delta/2 + dist.Rand()
+	}
+}
+
+// Locations implements the SymmetricGen interface.
+func (gen StudentTGen) Locations() (float64, float64) {
+	return gen.Delta / 2, -gen.Delta / 2
+}
+
+// A MixtureGen generates data from a mixture of DataGens.
+// Each pair of observations of the two groups is drawn from Gens[k] with probability proportional to Weights[k].
+type MixtureGen struct {
+	Weights []float64
+	Gens    []DataGen
+}
+
+// Generate implements the DataGen interface.
+func (gen MixtureGen) Generate(rnd *rand.Rand, n int) ([]float64, []float64) {
+	g1, g2 := make([]float64, n), make([]float64, n)
+	gen.GenerateInto(rnd, g1, g2)
+	return g1, g2
+}
+
+// GenerateInto implements the DataGenInto interface.
+func (gen MixtureGen) GenerateInto(rnd *rand.Rand, g1, g2 []float64) {
+	component := distuv.NewCategorical(gen.Weights, rnd)
+	for i := range g1 {
+		generateInto(gen.Gens[int(component.Rand())], rnd, g1[i:i+1], g2[i:i+1])
+	}
+}
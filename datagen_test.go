@@ -0,0 +1,90 @@
+package evalue
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestDataGen(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		gen   DataGen
+		mean1 float64
+		mean2 float64
+		vari  float64
+	}{
+		{gen: GaussianGen{Delta: 0.5}, mean1: 0.25, mean2: -0.25, vari: 1},
+		// The variance of Student's t-distribution is nu/(nu-2).
+		{gen: StudentTGen{Delta: 1, Nu: 5}, mean1: 0.5, mean2: -0.5, vari: 5. / 3},
+		// The variance of the mixture is the mean of the component variances plus the variance of the component means.
+		{gen: MixtureGen{Weights: []float64{3, 1}, Gens: []DataGen{GaussianGen{Delta: 0}, GaussianGen{Delta: 4}}}, mean1: 0.5, mean2: -0.5, vari: 1 + 0.75},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			rnd := rand.New(newDefaultRandSource())
+			const n = 100000
+			g1, g2 := test.gen.Generate(rnd, n)
+			if len(g1) != n || len(g2) != n {
+				t.Fatalf("unexpected lengths %d %d", len(g1), len(g2))
+			}
+			mean1, var1 := stat.MeanVariance(g1, nil)
+			mean2, var2 := stat.MeanVariance(g2, nil)
+			if !scalar.EqualWithinAbs(mean1, test.mean1, 0.02) || !scalar.EqualWithinAbs(mean2, test.mean2, 0.02) {
+				t.Errorf("unexpected means: got %f %f want %f %f", mean1, mean2, test.mean1, test.mean2)
+			}
+			if !scalar.EqualWithinRel(var1, test.vari, 0.05) || !scalar.EqualWithinRel(var2, test.vari, 0.05) {
+				t.Errorf("unexpected variances: got %f %f want %f", var1, var2, test.vari)
+			}
+		})
+	}
+}
+
+func TestGetNPlanDataGen(t *testing.T) {
+	t.Parallel()
+	const alpha, beta, deltaMin = 0.05, 0.2, 0.51765
+	// The default generator.
+	gaussian := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{DataGen: GaussianGen{Delta: deltaMin}})
+	if want := GetNPlan(alpha, beta, deltaMin); gaussian.N != want.N || gaussian.Mean != want.Mean {
+		t.Errorf("unexpected plan: got %d %d want %d %d", gaussian.N, gaussian.Mean, want.N, want.Mean)
+	}
+	// A larger true effect than deltaMin needs fewer samples.
+	larger := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{DataGen: GaussianGen{Delta: 2 * deltaMin}})
+	if !(larger.N < gaussian.N) {
+		t.Errorf("unexpected plan for a larger effect: got %d want less than %d", larger.N, gaussian.N)
+	}
+}
+
+// TestDataGenInto is not parallel, since testing.AllocsPerRun is not allowed in parallel tests.
+func TestDataGenInto(t *testing.T) {
+	tests := []struct {
+		gen DataGenInto
+		// allocs is the number of allocations, which does not grow with the number of observations.
+		allocs float64
+	}{
+		{gen: GaussianGen{Delta: 0.5}},
+		{gen: StudentTGen{Delta: 1, Nu: 5}},
+		// The categorical distribution of the components is allocated once per call.
+		{gen: MixtureGen{Weights: []float64{3, 1}, Gens: []DataGen{GaussianGen{Delta: 0}, StudentTGen{Delta: 4, Nu: 5}}}, allocs: 2},
+	}
+	for i, test := range tests {
+		const n = 100
+		want1, want2 := test.gen.Generate(rand.New(rand.NewPCG(uint64(i), 1)), n)
+		g1, g2 := make([]float64, n), make([]float64, n)
+		test.gen.GenerateInto(rand.New(rand.NewPCG(uint64(i), 1)), g1, g2)
+		if !slices.Equal(g1, want1) || !slices.Equal(g2, want2) {
+			t.Errorf("%d: GenerateInto differs from Generate", i)
+		}
+
+		rnd := rand.New(rand.NewPCG(uint64(i), 1))
+		allocs := testing.AllocsPerRun(100, func() { test.gen.GenerateInto(rnd, g1, g2) })
+		if allocs != test.allocs {
+			t.Errorf("%d: got %f allocations want %f", i, allocs, test.allocs)
+		}
+	}
+}
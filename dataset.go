@@ -0,0 +1,42 @@
+package evalue
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"strconv"
+)
+
+// grayCSV is the data of the Moral Typecasting study of the Many Labs 2 project, with columns study, group and value.
+// It is derived from https://github.com/ManyLabsOpenScience/ManyLabs2/blob/master/OSFdata/Moral%20Typecasting%20(Gray%20%26%20Wegner%2C%202009)/Gray.1/Global/Data/Gray_1_study_global_include_all_CLEAN_CASE.csv
+//
+//go:embed gray.csv
+var grayCSV []byte
+
+// ExampleDataset returns the data of the Moral Typecasting study of the Many Labs 2 project (Klein et al., 2018).
+// Each of the 61 studies was conducted at a different site, and consists of observations in the order they were collected.
+// Group 1 is the "Adult harms Baby" condition, group 2 is the "Baby harms Adult" condition, and values are Likert scale responses from 1 to 7.
+// Studies are sorted by the name of their site.
+//
+// Klein RA, Vianello M, Hasselman F, et al. Many Labs 2: Investigating Variation in Replicability Across Samples and Settings. Advances in Methods and Practices in Psychological Science. 2018;1(4):443-490. doi:10.1177/2515245918810225
+func ExampleDataset() [][]LabeledDatum {
+	rows, err := csv.NewReader(bytes.NewReader(grayCSV)).ReadAll()
+	if err != nil {
+		panic(err)
+	}
+	var studies [][]LabeledDatum
+	// Skip header.
+	for _, row := range rows[1:] {
+		study, err1 := strconv.Atoi(row[0])
+		group, err2 := strconv.Atoi(row[1])
+		value, err3 := strconv.ParseFloat(row[2], 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			panic("evalue: invalid example dataset row " + row[0] + "," + row[1] + "," + row[2])
+		}
+		for len(studies) <= study {
+			studies = append(studies, nil)
+		}
+		studies[study] = append(studies[study], LabeledDatum{Group: group, Value: value})
+	}
+	return studies
+}
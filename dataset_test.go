@@ -0,0 +1,37 @@
+package evalue
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExampleDataset(t *testing.T) {
+	t.Parallel()
+	studies := ExampleDataset()
+	if len(studies) != 61 {
+		t.Fatalf("wrong number of studies %d", len(studies))
+	}
+	for i, study := range studies {
+		var x, y []float64
+		for _, d := range study {
+			switch d.Group {
+			case 1:
+				x = append(x, d.Value)
+			case 2:
+				y = append(y, d.Value)
+			default:
+				t.Fatalf("study %d: invalid group %d", i, d.Group)
+			}
+		}
+		wantX, wantY := splitGray(grayData[i])
+		if !slices.Equal(x, wantX) || !slices.Equal(y, wantY) {
+			t.Errorf("study %d differs from the original data", i)
+		}
+	}
+
+	// The Carleton University study, see Example.
+	i := slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })
+	if res := RunSequential(studies[i], 0.05, 0.5176537); res.StopT != 30 {
+		t.Errorf("unexpected stopping time: got %d want %d", res.StopT, 30)
+	}
+}
@@ -1,5 +1,8 @@
 // Package evalue provides tools for performing e-value statistical tests.
 //
+// Functions and methods in this package neither modify nor retain the data slices passed to them,
+// so callers are free to reuse or truncate their slices after a call.
+//
 // References:
 //   - A. Ly, U. Boehm, G., A. Ramdas, D. van Ravenzwaaij. Safe Anytime-Valid Inference: Practical Maximally Flexible Sampling Designs for Experiments Based on e-Values, doi.org/10.31234/osf.io/h5vae
 package evalue
@@ -12,11 +15,20 @@ package evalue
 //   Informed Bayesian T-Tests: Online Appendix, Quentin F. Gronau, Alexander Ly, EJ Wagenmakers
 
 import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"math"
+	"math/big"
 	"math/rand/v2"
 	"slices"
+	"strconv"
 
 	"gonum.org/v1/exp/root"
+	"gonum.org/v1/gonum/integrate/quad"
 	"gonum.org/v1/gonum/mathext"
 	"gonum.org/v1/gonum/stat"
 	"gonum.org/v1/gonum/stat/distuv"
@@ -26,65 +38,589 @@ import (
 // that has not been stopped by a statistical test.
 const notStopped = -1
 
+// convergenceCheckInterval is the number of simulations between checks of GetNPlanOptions.ConvergenceTol.
+const convergenceCheckInterval = 100
+
 // A Mom is an e-process based on a non-local moment prior.
 type Mom struct {
-	// G is the tuning parameter of the mom e-process.
+	// G is the tuning parameter of the mom e-process, which sets the scale of its prior of the effect size.
+	// NewMom ties G to the minimal effect size deltaMin, but G may be set independently of the effect size used for planning, see GetNPlanOptions.Mom.
 	G float64
+
+	// Prec, if positive, is the number of mantissa bits of the big.Float arithmetic used to compute e-values.
+	// High precision is much slower, and is mainly useful for validating the float64 computation on large samples or extreme effects.
+	Prec uint
 }
 
 // NewMom creates a mom e-process.
 // deltaMin is a lower bound of the true effect size based on domain knowledge.
 // The returned mom e-process is tuned such that it rejects the null hypothesis at the fastest rate, when the true data generating process has effect size deltaMin.
+// Since the mom e-process is two-sided, only the magnitude of deltaMin matters, and NewMom(-deltaMin) equals NewMom(deltaMin).
+// For a directional alternative hypothesis, see NewOneSidedMom, which retains the sign of deltaMin.
 func NewMom(deltaMin float64) *Mom {
 	return &Mom{G: deltaMin * deltaMin / 2}
 }
 
 // EValue returns the e-value of the two sample data.
+// The e-value lies in [0, +Inf], and never panics on user data.
+// It is 1 if there are too few observations to estimate the variance, that is if either group is empty or both groups have a single observation.
+// It is NaN if the t-statistic is undefined, which happens when the data contain NaN or infinite values, or values so large that their variance overflows.
+// It is +Inf once the e-value exceeds the range of float64, which happens for strong evidence at large sample sizes.
+// An overflowed e-value still exceeds 1/alpha for every alpha in (0, 1), so the decision to reject the null hypothesis is unaffected, see LogEValue for its magnitude.
+//
+// The test is two-sided, and the e-value depends on the t-statistic only through T*T, so swapping the group labels leaves it unchanged: EValue(x, y) equals EValue(y, x) exactly.
 func (p *Mom) EValue(x, y []float64) float64 {
+	return p.EValuePhi0(x, y, 0)
+}
+
+// LogEValue returns the natural logarithm of the e-value of the two sample data.
+// It remains finite where EValue overflows to +Inf, by falling back to high precision arithmetic, and so is suited for comparing or accumulating overwhelming evidence.
+func (p *Mom) LogEValue(x, y []float64) float64 {
+	e := p.EValue(x, y)
+	if !math.IsInf(e, 1) {
+		return math.Log(e)
+	}
 	t := TStat(x, y, 0)
-	s := p.eValue(t.T, t.Nu, t.NEff)
-	return s
+	return p.logEValue(t.T, t.Nu, t.NEff)
+}
+
+// logEValue returns the natural logarithm of the e-value of a t-statistic, falling back to high precision arithmetic where the e-value overflows.
+func (p *Mom) logEValue(t, nu, nEff float64) float64 {
+	e := p.eValue(t, nu, nEff)
+	if !math.IsInf(e, 1) {
+		return math.Log(e)
+	}
+	return recoverNaN(func() float64 {
+		mant := new(big.Float)
+		exp := eValueBig(p.G, t, nu, nEff, 64).MantExp(mant)
+		m, _ := mant.Float64()
+		return math.Log(m) + float64(exp)*math.Ln2
+	})
+}
+
+// EValuePhi0 returns the e-value of the two sample data under the null hypothesis that the difference between the group means is phi0.
+// phi0 lies inside the confidence interval returned by CI if and only if its e-value is less than 1/alpha.
+//
+// If both groups are constant, the t-statistic is undefined, and EValuePhi0 returns 1, as for too few observations.
+// Constant groups carry no evidence, since their zero variance says nothing about the variance of future observations,
+// and they are common early in experiments with discrete data, such as Likert scales.
+// CI correspondingly returns the infinite interval, and MomStream.EValue returns 1 as well.
+func (p *Mom) EValuePhi0(x, y []float64, phi0 float64) float64 {
+	return p.EValueFromTStat(TStat(x, y, phi0))
+}
+
+// EValueFromTStat returns the e-value of the t-statistic t, which is typically computed by TStat.
+// Like EValue, it returns 1 if there are too few observations to estimate the variance, and NaN if t is undefined.
+func (p *Mom) EValueFromTStat(t TStatistic) float64 {
+	if !t.sufficient() {
+		return 1
+	}
+	if !t.defined() {
+		return math.NaN()
+	}
+	// Constant groups, whose T is NaN if the difference between the group means is phi0, and infinite otherwise.
+	if t.Sp == 0 {
+		return 1
+	}
+	return p.eValue(t.T, t.Nu, t.NEff)
+}
+
+// EValueInto is equivalent to EValue, but also stores the t-statistic of the two sample data in the caller-provided scratch.
+// It is no faster than EValue, which does not allocate either, and exists only for callers who need the t-statistic as well, without computing it twice.
+func (p *Mom) EValueInto(x, y []float64, scratch *TStatistic) float64 {
+	*scratch = TStat(x, y, 0)
+	return p.EValueFromTStat(*scratch)
+}
+
+// EValueFromTStream returns the e-value trajectory of a stream of t-statistics computed elsewhere, such as from a regression.
+// The i-th e-value is that of the t-statistic ts[i] with nus[i] degrees of freedom and effective sample size nEffs[i].
+// It panics if the lengths of ts, nus and nEffs differ.
+func (p *Mom) EValueFromTStream(ts, nus, nEffs []float64) []float64 {
+	if len(nus) != len(ts) || len(nEffs) != len(ts) {
+		panic("evalue: length mismatch")
+	}
+	eValues := make([]float64, len(ts))
+	for i, t := range ts {
+		eValues[i] = p.eValue(t, nus[i], nEffs[i])
+	}
+	return eValues
+}
+
+// EValueAtCounts returns the e-values of the prefixes x[:c] and y[:c] of the two sample data, for each count c in counts.
+// It computes the means and squared deviations of all prefixes in a single pass by Welford's algorithm, and so is much faster than calling EValue on each prefix.
+// It panics if a count exceeds the length of x or y.
+func (p *Mom) EValueAtCounts(x, y []float64, counts []int) []float64 {
+	sampleLen := 0
+	for _, c := range counts {
+		sampleLen = max(sampleLen, c)
+	}
+	mean1, m21 := prefixMoments(x[:sampleLen])
+	mean2, m22 := prefixMoments(y[:sampleLen])
+
+	eValues := make([]float64, len(counts))
+	for i, c := range counts {
+		// There are too few observations to estimate the variance.
+		if c < 2 {
+			eValues[i] = 1
+			continue
+		}
+		n := float64(c)
+		ts := TStatistic{Nu: 2*n - 2, NEff: EffectiveSampleSize(c, c), Mean1: mean1[c-1], Mean2: mean2[c-1]}
+		ts.Sp = math.Sqrt((m21[c-1] + m22[c-1]) / ts.Nu)
+		ts.T = math.Sqrt(ts.NEff) * (ts.Mean1 - ts.Mean2) / ts.Sp
+		eValues[i] = p.EValueFromTStat(ts)
+	}
+	return eValues
+}
+
+// EValueWindow returns the e-value of the last window observations of each group.
+// The windowed e-value forfeits the anytime-valid guarantee of EValue, since it discards evidence accumulated before the window.
+// In return, it reacts faster to changes when monitoring non-stationary data.
+func (p *Mom) EValueWindow(x, y []float64, window int) float64 {
+	return p.EValue(x[max(0, len(x)-window):], y[max(0, len(y)-window):])
+}
+
+// EValueCapped returns the e-value of the two sample data, capped at the ceiling cap.
+// Capping guards downstream computations against numerical blowup of huge e-values.
+// A capped e-value is still a valid e-value, since it never exceeds the uncapped one, and with cap > 1/alpha the decision to reject the null hypothesis is unchanged.
+func (p *Mom) EValueCapped(x, y []float64, cap float64) float64 {
+	return min(p.EValue(x, y), cap)
+}
+
+// MinRejectAlpha returns min(1, 1/EValue), the infimum of the significance levels at which the e-value of the two sample data rejects the null hypothesis.
+// Since the null hypothesis is rejected only if the e-value strictly exceeds 1/alpha, the sequential test rejects at alpha once MinRejectAlpha is less than alpha,
+// whereas at alpha equal to MinRejectAlpha the e-value merely equals 1/alpha, up to rounding, and the test does not reject.
+// MinRejectAlpha is NaN if the e-value is NaN, see EValue, in which case the test never rejects.
+// Like a p-value, it is small for strong evidence, and its running minimum over the course of an experiment is an anytime-valid p-value:
+// under the null hypothesis, the probability that it ever drops to alpha or below is at most alpha by Ville's inequality.
+func (p *Mom) MinRejectAlpha(x, y []float64) float64 {
+	return min(1, 1/p.EValue(x, y))
 }
 
 // eValue returns the e-value of a t-statistic.
-// See equation B4 in Ly for more details.
+// It returns 1 if nu or nEff is not positive, since there is no evidence without an estimate of the variance.
 func (p *Mom) eValue(t, nu, nEff float64) float64 {
+	if !(nu > 0 && nEff > 0) {
+		return 1
+	}
+	if p.Prec > 0 {
+		return eValueBigFloat64(p.G, t, nu, nEff, p.Prec)
+	}
+	return eValueG(t, nu, nEff, p.G)
+}
+
+// eValueG returns the e-value of a t-statistic, for the mom e-process with tuning parameter g.
+// See equation B4 in Ly for more details.
+//
+// The argument z of the hypergeometric function is at most nEff*g/(1+nEff*g) for any t, so a small nEff, such as that of highly unbalanced groups, keeps z far from 1 where the hypergeometric function is accurate.
+// The e-value is then accurate to a relative error of about 1e-13, until it overflows.
+func eValueG(t, nu, nEff, g float64) float64 {
 	const k = 1
-	g := p.G
-	e1 := math.Pow(1+nEff*g, -k-1./2)
-	e2 := mathext.Hypergeo((nu+1)/2, k+1./2, 1./2, t*t/(nu+t*t)*nEff*g/(1+nEff*g))
+	e1, e2, z := eValueComponents(t, nu, nEff, g)
+	// Hypergeo overflows to NaN or Inf for large nu and z close to 1, even if the e-value itself is representable.
+	if (math.IsNaN(e2) || math.IsInf(e2, 1)) && z >= 0 && z < 1 {
+		// All terms of the hypergeometric series are positive, so the e-value overflows if any single term times e1 does.
+		// The largest term is around the k whose term ratio z(a+k)(b+k)/((c+k)(k+1)) is one.
+		a, b, c := (nu+1)/2, k+1./2, 1./2
+		kMax := math.Floor(z * a / (1 - z))
+		lg := func(x float64) float64 { l, _ := math.Lgamma(x); return l }
+		logTerm := lg(a+kMax) - lg(a) + lg(b+kMax) - lg(b) - lg(c+kMax) + lg(c) - lg(kMax+1) + kMax*math.Log(z)
+		if math.Log(e1)+logTerm > math.Log(math.MaxFloat64) {
+			return math.Inf(1)
+		}
+		return eValueBigFloat64(g, t, nu, nEff, 64)
+	}
 	return e1 * e2
 }
 
+// eValueComponents returns the prefactor e1 and the hypergeometric term e2 of the e-value, whose product is the e-value, and the argument z of the hypergeometric function.
+func eValueComponents(t, nu, nEff, g float64) (e1, e2, z float64) {
+	const k = 1
+	e1 = math.Pow(1+nEff*g, -k-1./2)
+	t2 := t * t / (nu + t*t)
+	// t^2/(nu+t^2) tends to 1 when t^2 overflows.
+	if math.IsInf(t*t, 1) {
+		t2 = 1
+	}
+	z = t2 * nEff * g / (1 + nEff*g)
+	e2 = hypergeo((nu+1)/2, k+1./2, 1./2, z)
+	return e1, e2, z
+}
+
+// hypergeo returns the hypergeometric function 2F1(a, b; c; z) computed by mathext.Hypergeo, or NaN if it panics.
+// Some versions of gonum panic on boundary arguments, and a NaN instead lets the e-value fall back to high precision arithmetic,
+// so that a single pathological step does not abort a long experiment.
+func hypergeo(a, b, c, z float64) float64 {
+	return recoverNaN(func() float64 { return mathext.Hypergeo(a, b, c, z) })
+}
+
+// eValueBigFloat64 returns the e-value computed by eValueBig rounded to float64, or NaN if the big.Float arithmetic panics, which it does on infinite intermediate results such as Inf/Inf.
+func eValueBigFloat64(g, t, nu, nEff float64, prec uint) float64 {
+	return recoverNaN(func() float64 {
+		e, _ := eValueBig(g, t, nu, nEff, prec).Float64()
+		return e
+	})
+}
+
+// recoverNaN returns f(), or NaN if f panics.
+func recoverNaN(f func() float64) (v float64) {
+	defer func() {
+		if recover() != nil {
+			v = math.NaN()
+		}
+	}()
+	return f()
+}
+
+// EValueComponents returns the two multiplicative components of the e-value of a t-statistic with nu degrees of freedom and effective sample size nEff.
+// prefactor is (1+nEff*G)^(-3/2), and hyper is the hypergeometric function 2F1((nu+1)/2, 3/2; 1/2; z) evaluated in float64.
+// They help diagnose numerical issues, such as whether an overflow comes from the prefactor or the hypergeometric function.
+// hyper may overflow to NaN or +Inf, in which case EValue falls back to high precision arithmetic, and their product differs from the e-value.
+func (p *Mom) EValueComponents(t, nu, nEff float64) (prefactor, hyper float64) {
+	prefactor, hyper, _ = eValueComponents(t, nu, nEff, p.G)
+	return prefactor, hyper
+}
+
+// EValueNull returns the e-value at t=0, when there is no observed difference between the groups.
+// Since the hypergeometric function is 1 at t=0, the e-value reduces to the prefactor (1+nEff*G)^(-3/2), which does not depend on nu.
+// It is the baseline from which the e-value grows as evidence against the null hypothesis accumulates.
+func (p *Mom) EValueNull(nu, nEff float64) float64 {
+	return math.Pow(1+nEff*p.G, -3./2)
+}
+
+// NullQuantile returns the q-quantile of the e-value under the null hypothesis, for a t-statistic with nu degrees of freedom and effective sample size nEff.
+// Since the e-value increases with |t|, and t follows the central t-distribution under the null hypothesis, the quantile is the e-value at the (1+q)/2 quantile of the t-distribution.
+// By Markov's inequality, NullQuantile(nu, nEff, 1-alpha) is at most 1/alpha.
+func (p *Mom) NullQuantile(nu, nEff, q float64) float64 {
+	t := distuv.StudentsT{Sigma: 1, Nu: nu}.Quantile((1 + q) / 2)
+	return p.eValue(t, nu, nEff)
+}
+
+// CrossingProbability estimates by simulation the probability that the e-value ever exceeds threshold under the null hypothesis, when monitored after each observation up to the sample size with nu degrees of freedom and effective sample size nEff.
+// The group sizes at that sample size are n1+n2 = nu+2 and n1*n2/(n1+n2) = nEff, and both groups grow in proportion.
+// Each of the numSamples simulations draws Gaussian data without effect from the random source rsrc, which defaults to a fixed source if nil.
+// By Ville's inequality, the probability is at most 1/threshold, whatever the sample size.
+//
+// It returns NaN if no group sizes have the given nu and nEff, that is unless nu is finite and non-negative and 0 < nEff <= (nu+2)/4,
+// or if threshold is not positive or numSamples is less than 1.
+func (p *Mom) CrossingProbability(nu, nEff, threshold float64, numSamples int, rsrc rand.Source) float64 {
+	// The effective sample size of groups of total size s is positive and at most s/4, which is attained by groups of equal size.
+	s := nu + 2
+	if math.IsInf(nu, 1) || !(nu >= 0 && nEff > 0 && nEff <= s/4) || !(threshold > 0) || numSamples < 1 {
+		return math.NaN()
+	}
+	if rsrc == nil {
+		rsrc = newDefaultRandSource()
+	}
+
+	// Recover the group sizes from nu and nEff.
+	n1 := math.Round((s + math.Sqrt(max(0, s*s-4*nEff*s))) / 2)
+	n2 := max(1, s-n1)
+	opt := GetNPlanOptions{
+		Ratio:          n2 / n1,
+		NumSimulations: numSamples,
+		Rsrc:           rsrc,
+		DataGen:        GaussianGen{},
+		Mom:            p,
+		Context:        context.Background(),
+		Progress:       func(int, int) {},
+	}
+	return simulateNPlan(1/threshold, 0, int(n1), int(n2), opt).Power()
+}
+
+// EValueApproxSmallT returns the second order Taylor approximation in t of the e-value of a t-statistic.
+// It keeps the leading terms of the hypergeometric series, and so avoids evaluating the hypergeometric function.
+// Letting r=nEff*G/(1+nEff*G), the relative error is of order (r*t*t)^2 + r*t^4/nu, and is less than 1% if both r*t*t < 0.1 and t*t < 0.1*nu.
+func (p *Mom) EValueApproxSmallT(t, nu, nEff float64) float64 {
+	ng := nEff * p.G
+	r := ng / (1 + ng)
+	return math.Pow(1+ng, -3./2) * (1 + 3*(nu+1)/(2*nu)*r*t*t)
+}
+
 // CI returns the confidence interval of the two sample data.
+// The interval consists of all phi0 whose EValuePhi0 is less than 1/alpha.
+// alpha must lie in (0, 1), otherwise the interval is [NaN, NaN].
+// The interval is [-Inf, +Inf] if there are too few observations to estimate the variance, and [NaN, NaN] if the t-statistic is undefined, see EValue.
+// The interval is centered at the directional difference Mean1-Mean2, so swapping the group labels mirrors it: CI(y, x, alpha) is [-hi, -lo] for CI(x, y, alpha) = [lo, hi].
 func (p *Mom) CI(x, y []float64, alpha float64) [2]float64 {
 	t := TStat(x, y, 0)
-	nu, nEff := t.Nu, t.NEff
-	f := func(t float64) float64 { return p.eValue(t, nu, nEff) - 1./alpha }
+	return ciOfT(t, p.CriticalT(t.Nu, t.NEff, alpha))
+}
+
+// CIWithCritical is like CI, but also returns the critical t-statistic criticalT used to construct the interval, see CriticalT.
+// The half width of a finite interval is Sp/sqrt(NEff)*criticalT.
+func (p *Mom) CIWithCritical(x, y []float64, alpha float64) (ci [2]float64, criticalT float64) {
+	t := TStat(x, y, 0)
+	criticalT = p.CriticalT(t.Nu, t.NEff, alpha)
+	return ciOfT(t, criticalT), criticalT
+}
+
+// CIMulti returns the confidence intervals of CI for each of alphas, such as the 90%, 95% and 99% intervals for reporting.
+// The critical t-statistics are solved from the largest alpha to the smallest, each starting from the previous one, since smaller alphas give wider intervals.
+func (p *Mom) CIMulti(x, y []float64, alphas []float64) [][2]float64 {
+	t := TStat(x, y, 0)
+	order := make([]int, len(alphas))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int { return cmp.Compare(alphas[j], alphas[i]) })
+
+	cis := make([][2]float64, len(alphas))
+	var lo float64
+	for _, i := range order {
+		tAlpha := p.criticalTAbove(t.Nu, t.NEff, alphas[i], lo)
+		if !math.IsNaN(tAlpha) {
+			lo = tAlpha
+		}
+		cis[i] = ciOfT(t, tAlpha)
+	}
+	return cis
+}
+
+// CIExcludes reports whether the confidence interval ci excludes value.
+// It coincides with rejecting the null hypothesis phi0=value, that is EValuePhi0 exceeding 1/alpha.
+// The boundaries of ci, where the e-value equals 1/alpha, are not excluded, and neither are values inside infinite bounds.
+func CIExcludes(ci [2]float64, value float64) bool {
+	return value < ci[0] || value > ci[1]
+}
+
+// ciOfT returns the confidence interval of the t-statistic t, given the critical t-statistic tAlpha.
+func ciOfT(t TStatistic, tAlpha float64) [2]float64 {
+	if math.IsNaN(tAlpha) {
+		return [2]float64{math.NaN(), math.NaN()}
+	}
+	if !t.sufficient() {
+		return [2]float64{math.Inf(-1), math.Inf(1)}
+	}
+	if !t.defined() {
+		return [2]float64{math.NaN(), math.NaN()}
+	}
+	// Constant groups, whose e-value is 1 for every phi0, exclude nothing.
+	if math.IsInf(tAlpha, 1) || t.Sp == 0 {
+		return [2]float64{math.Inf(-1), math.Inf(1)}
+	}
+	width := t.Sp / math.Sqrt(t.NEff) * tAlpha
+	mean := t.Mean1 - t.Mean2
+	return [2]float64{mean - width, mean + width}
+}
+
+// CIEffectSize returns the confidence interval of the standardized effect size, or Cohen's d, of the two sample data.
+// It is the interval returned by CI in units of the pooled standard deviation Sp, and so consists of all delta whose EValuePhi0 at phi0=delta*Sp is less than 1/alpha.
+// Like CI, it returns [NaN, NaN] if alpha does not lie in (0, 1).
+func (p *Mom) CIEffectSize(x, y []float64, alpha float64) [2]float64 {
+	t := TStat(x, y, 0)
+	tAlpha := p.CriticalT(t.Nu, t.NEff, alpha)
+	if math.IsNaN(tAlpha) || (t.sufficient() && !t.defined()) {
+		return [2]float64{math.NaN(), math.NaN()}
+	}
+	if math.IsInf(tAlpha, 1) || t.Sp == 0 {
+		return [2]float64{math.Inf(-1), math.Inf(1)}
+	}
+	width := tAlpha / math.Sqrt(t.NEff)
+	d := (t.Mean1 - t.Mean2) / t.Sp
+	return [2]float64{d - width, d + width}
+}
+
+// CriticalT returns the t-statistic with nu degrees of freedom and effective sample size nEff, whose e-value is 1/alpha.
+// It returns +Inf if no such t-statistic exists, for example when nu or nEff is not positive, and NaN if alpha does not lie in (0, 1).
+func (p *Mom) CriticalT(nu, nEff, alpha float64) float64 {
+	return p.criticalTAbove(nu, nEff, alpha, 0)
+}
+
+// criticalTAbove is like CriticalT, but searches only above lo, which is a lower bound of the critical t.
+func (p *Mom) criticalTAbove(nu, nEff, alpha, lo float64) float64 {
+	if !validProbability(alpha) {
+		return math.NaN()
+	}
+	if !(nu > 0 && nEff > 0) || math.IsInf(lo, 1) {
+		return math.Inf(1)
+	}
+	return solveCriticalT(func(t float64) float64 { return p.eValue(t, nu, nEff) - 1./alpha }, lo)
+}
 
-	// Construct straddle [a, b] to be fed into Brent's method.
-	// Since f(0) < 0 always, a=0.
-	const a = 0
+// solveCriticalT returns the root of f above a, where f(a) < 0 and f increases with t, or +Inf if the root is not found.
+// a is typically 0, or a lower bound of the root such as the critical t of a larger alpha.
+func solveCriticalT(f func(float64) float64, a float64) float64 {
 	// Since the two-sided 95% t-value for the smallest sample size of 1 is 12.706, start the search from around 12.
-	var b float64 = 12
-	var maxB float64 = b * math.Pow(2, 15)
+	guess := max(12, 2*a)
 	tol := math.Nextafter(1, 2) - 1
-	// Solve for tAlpha, where f(tAlpha)=0.
-	var tAlpha float64
-	var err error
-	for ; b < maxB; b *= 2 {
-		tAlpha, err = root.Brent(f, a, b, tol)
-		if err == nil {
-			break
+	tAlpha, err := solveBrentMono(f, guess, tol)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return tAlpha
+}
+
+// RejectionRegion returns the thresholds of the t-statistic with nu degrees of freedom and effective sample size nEff, beyond which the e-value exceeds 1/alpha.
+// The null hypothesis is rejected if and only if t < lo or t > hi, where lo = -hi = -CriticalT(nu, nEff, alpha).
+// The region is narrower than that of the classical t-test, whose thresholds are ±ClassicalCriticalT(nu, alpha).
+func (p *Mom) RejectionRegion(nu, nEff, alpha float64) (lo, hi float64) {
+	hi = p.CriticalT(nu, nEff, alpha)
+	return -hi, hi
+}
+
+// ClassicalCriticalT returns the critical value of the classical two-sided t-test with nu degrees of freedom at the significance level alpha.
+// Comparing it with the critical t of an e-value test shows the price paid for anytime validity.
+func ClassicalCriticalT(nu, alpha float64) float64 {
+	return distuv.StudentsT{Sigma: 1, Nu: nu}.Quantile(1 - alpha/2)
+}
+
+// PValueStudent returns the two-sided p-value of the classical t-test of the t-statistic ts.
+// It is a deterministic function of ts and needs no random source, unlike sampling from distuv.StudentsT.
+// The p-value is only valid at a sample size fixed in advance, see EValue for a test that allows optional stopping.
+func PValueStudent(ts TStatistic) float64 {
+	return 2 * distuv.StudentsT{Sigma: 1, Nu: ts.Nu}.Survival(math.Abs(ts.T))
+}
+
+// ExpectedEValue returns the expected e-value of the mom e-process p at the group sizes n1 and n2, when the true effect size is delta.
+// Under the alternative, the t-statistic follows a noncentral t distribution, whose quantiles also drive the sample size without early stopping in GetNPlan.
+// Under the null hypothesis delta=0, the expected e-value is 1.
+//
+// The far tails of the noncentral t density underflow, so the expected e-value is underestimated when it is astronomically large.
+func ExpectedEValue(n1, n2 int, delta float64, p *Mom) float64 {
+	// The e-value is a likelihood ratio, whose expectation under the null hypothesis is exactly 1.
+	// Returning it directly also avoids the overflow of the e-value in the tails at large sample sizes.
+	if delta == 0 {
+		return 1
+	}
+	m1, m2 := float64(n1), float64(n2)
+	nu, nEff := m1+m2-2, m1*m2/(m1+m2)
+	mu := Noncentrality(nEff, delta)
+	prob := distuv.NoncentralT{Nu: nu, Mu: mu}.Prob
+	f := func(t float64) float64 {
+		pt := prob(t)
+		// Avoid multiplying zero by an overflowed e-value in the far tails.
+		if pt == 0 {
+			return 0
+		}
+		return p.eValue(t, nu, nEff) * pt
+	}
+
+	// Split the integral at the noncentrality parameter, so that the mode of the integrand is resolved by both halves.
+	const n = 1024
+	return quad.Fixed(f, math.Inf(-1), mu, n, nil, 0) + quad.Fixed(f, mu, math.Inf(1), n, nil, 0)
+}
+
+// CurrentMDE returns the minimum detectable effect at the current group sizes of the two sample data.
+// It is the smallest effect size delta, whose ExpectedEValue at the current group sizes exceeds 1/alpha.
+// Only the sizes of x and y matter, and +Inf is returned if no effect size is detectable, for example when the groups are too small.
+func (p *Mom) CurrentMDE(x, y []float64, alpha float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 || n1+n2 <= 2 {
+		return math.Inf(1)
+	}
+	f := func(delta float64) float64 { return math.Log(ExpectedEValue(n1, n2, delta, p)) + math.Log(alpha) }
+
+	// Find the bracket [a, b] that contains the root.
+	// Since the expected e-value is 1 at delta=0, f(0) < 0.
+	const maxDelta = 1 << 10
+	a, b := 0., 1./8
+	for ; f(b) < 0; a, b = b, 2*b {
+		if b > maxDelta {
+			return math.Inf(1)
 		}
 	}
+	eps := math.Nextafter(1, 2) - 1
+	tol := math.Pow(eps, 0.25)
+	delta, err := root.Brent(f, a, b, tol)
 	if err != nil {
-		return [2]float64{math.Inf(-1), math.Inf(1)}
+		return math.Inf(1)
 	}
+	return delta
+}
 
-	width := t.Sp / math.Sqrt(nEff) * tAlpha
-	mean := t.Mean1 - t.Mean2
-	return [2]float64{mean - width, mean + width}
+// maxForecast is the largest number of additional observations forecasted by ForecastRemaining.
+const maxForecast = 1 << 20
+
+// ForecastRemaining returns the number of additional observations of the two sample data, after which the ExpectedEValue exceeds 1/alpha, assuming that the observed standardized effect size is the true effect size.
+// The additional observations are split between the two groups in their current proportion.
+// It returns 0 if the expected e-value at the current group sizes already exceeds 1/alpha, and -1 if the observed effect size is zero or undefined, alpha is invalid, or more than 1<<20, about a million, observations are needed.
+//
+// The forecast is as noisy as the observed effect size, which is itself a biased estimate when the experiment is monitored with optional stopping.
+func (p *Mom) ForecastRemaining(x, y []float64, alpha float64) int {
+	t := TStat(x, y, 0)
+	if !validProbability(alpha) || !t.sufficient() || !(t.Sp > 0) {
+		return notStopped
+	}
+	delta := (t.Mean1 - t.Mean2) / t.Sp
+	if delta == 0 || math.IsNaN(delta) || math.IsInf(delta, 0) {
+		return notStopped
+	}
+
+	n1, n2 := len(x), len(y)
+	frac := float64(n1) / float64(n1+n2)
+	reaches := func(m int) bool {
+		m1 := int(math.Round(float64(m) * frac))
+		return ExpectedEValue(n1+m1, n2+m-m1, delta, p) > 1./alpha
+	}
+	if reaches(0) {
+		return 0
+	}
+	// Bracket the number of additional observations by doubling, and then bisect.
+	lo, hi := 0, 1
+	for !reaches(hi) {
+		if hi == maxForecast {
+			return notStopped
+		}
+		lo, hi = hi, min(2*hi, maxForecast)
+	}
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if reaches(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
+
+// SamplesForTargetE returns the smallest group sizes n1 and n2=ceil(ratio*n1), at which the expected e-value of p reaches targetE when the true effect size is delta.
+// Unlike GetNPlan which plans for statistical power, it plans for the average strength of evidence.
+// It returns -1, -1 if targetE is out of reach, for example when delta is zero.
+func SamplesForTargetE(targetE, delta float64, p *Mom, ratio float64) (int, int) {
+	n2Of := func(n1 int) int { return max(1, int(math.Ceil(ratio*float64(n1)))) }
+	reached := func(n1 int) bool {
+		n2 := n2Of(n1)
+		if n1+n2 <= 2 {
+			return false
+		}
+		return ExpectedEValue(n1, n2, delta, p) >= targetE
+	}
+
+	// Find the bracket (a, b] that contains the smallest n1.
+	const maxN1 = 1 << 20
+	a, b := 1, 2
+	for ; !reached(b); a, b = b, 2*b {
+		if b > maxN1 {
+			return -1, -1
+		}
+	}
+	if reached(a) {
+		return a, n2Of(a)
+	}
+	// Binary search inside the bracket.
+	for b-a > 1 {
+		m := (a + b) / 2
+		if reached(m) {
+			b = m
+		} else {
+			a = m
+		}
+	}
+	return b, n2Of(b)
+}
+
+var (
+	// ErrInvalidOptions is returned when options are invalid.
+	ErrInvalidOptions = errors.New("evalue: invalid options")
+	// ErrInvalidProbability is returned when a probability, such as the significance level alpha, does not lie in (0, 1).
+	ErrInvalidProbability = errors.New("evalue: probability not in (0, 1)")
+)
+
+// validProbability reports whether p lies in (0, 1).
+func validProbability(p float64) bool {
+	return 0 < p && p < 1
 }
 
 // GetNPlanOptions are options for GetNPlan.
@@ -92,20 +628,50 @@ type GetNPlanOptions struct {
 	// Ratio is the size ratio between the two groups in our sample.
 	Ratio float64
 
+	// N2, if positive, fixes the size of the second group, such as when the control group consists of limited historical data.
+	// Only the first group grows until the desired power is reached, and Ratio is ignored.
+	N2 int
+
 	// NumSimulations is the number of simulations performed.
 	NumSimulations int
 
+	// ConvergenceTol, if positive, stops the simulations early once the planned sample size N stabilizes.
+	// N is checked every 100 simulations, and is considered stable when it differs relatively by at most ConvergenceTol from the N of the first half of the simulations.
+	// NumSimulations is then the maximum number of simulations, and the number actually performed is len(NPlan.StopT).
+	ConvergenceTol float64
+
 	// RandSource is the random source used in simulations.
 	Rsrc rand.Source
+
+	// DataGen generates the data in simulations.
+	// It defaults to Gaussian data with effect size deltaMin.
+	DataGen DataGen
+
+	// Antithetic pairs each simulation with its antithetic simulation, whose data are reflected about the group locations.
+	// The negatively correlated pairs reduce the Monte Carlo variance of N and Mean.
+	// It requires a DataGen that implements SymmetricGen, such as GaussianGen and StudentTGen.
+	Antithetic bool
+
+	// Mom is the mom e-process whose e-values are simulated.
+	// It defaults to NewMom(deltaMin), and can be set to decouple the prior scale of the e-process from the planning effect size deltaMin.
+	Mom *Mom
+
+	// Context cancels the simulations, in which case GetNPlanErr returns the NPlan of the simulations completed so far together with the error of the context.
+	// It defaults to context.Background().
+	Context context.Context
+	// Progress, if non-nil, is called after each simulation with the number of completed simulations and NumSimulations.
+	Progress func(done, total int)
 }
 
 // NPlan is the planned sample size of an experiment.
+// Sample sizes are those of the first group, and are -1 if the desired power cannot be reached.
 type NPlan struct {
 	// N is the planned sample size with early stopping.
 	N int
 	// Mean is the average sample size for rejecting the null hypothesis with early stopping.
 	Mean int
 	// Batch is the sample size without early stopping.
+	// N, Mean and Batch are -1 if the desired power cannot be reached, for example when deltaMin is zero.
 	Batch int
 
 	// EValue is the e-values during simulation.
@@ -117,45 +683,82 @@ type NPlan struct {
 // GetNPlan returns the planned sample size of an experiment.
 // alpha is the significance level, and beta is one minus statistical power.
 // deltaMin is a lower bound of the true effect size based on domain knowledge.
+//
+// GetNPlan returns the zero NPlan if the arguments are invalid, see GetNPlanErr for the reason.
 func GetNPlan(alpha, beta, deltaMin float64, options ...GetNPlanOptions) NPlan {
-	var opt GetNPlanOptions
-	if len(options) > 0 {
-		opt = options[0]
-	}
-	if opt.Ratio == 0 {
-		opt.Ratio = 1
-	}
-	if opt.NumSimulations == 0 {
-		opt.NumSimulations = 1000
-	}
-	if opt.Rsrc == nil {
-		opt.Rsrc = rand.NewChaCha8([32]byte{0x01, 0x08, 0x02, 0x08, 0x83, 0x15, 0x07, 0x19, 0x64, 0x7a, 0x64, 0x5f, 0x71, 0x7e, 0x07, 0x01, 0xd9, 0x80, 0x61, 0xed, 0xce, 0xaa, 0x4e, 0xf2, 0x2f, 0x36, 0xb5, 0x18, 0x82, 0x85, 0x07, 0x01})
+	nPlan, _ := GetNPlanErr(alpha, beta, deltaMin, options...)
+	return nPlan
+}
+
+// GetNPlanErr is like GetNPlan, but returns an error wrapping ErrInvalidProbability if alpha or beta does not lie in (0, 1),
+// or wrapping ErrInvalidOptions if the options are invalid.
+// If GetNPlanOptions.Context is cancelled, it returns the NPlan of the simulations completed so far together with the error of the context.
+// Zero options take their default values, whereas a negative or NaN Ratio, a negative NumSimulations, a negative N2, a negative or NaN ConvergenceTol, or Antithetic with a DataGen that is not a SymmetricGen is invalid.
+func GetNPlanErr(alpha, beta, deltaMin float64, options ...GetNPlanOptions) (NPlan, error) {
+	opt, err := newGetNPlanOptions(alpha, beta, deltaMin, options)
+	if err != nil {
+		return NPlan{}, err
 	}
 
 	// Bound the length of a simulation by the sample size in batch mode.
 	// Experiments with early stopping always need smaller sample sizes than those in batch mode which are done without early stopping.
-	p := NewMom(deltaMin)
-	nPlanBatch1, nPlanBatch2 := getNPlanBatch(alpha, beta, deltaMin, opt.Ratio, p)
-	nPlan := NPlan{Batch: nPlanBatch1}
+	nPlanBatch1, nPlanBatch2 := batchSizes(alpha, beta, deltaMin, opt)
+	if nPlanBatch1 < 0 {
+		return NPlan{N: notStopped, Mean: notStopped, Batch: notStopped}, nil
+	}
+	nPlan := simulateNPlan(alpha, beta, nPlanBatch1, nPlanBatch2, opt)
+	nPlan.Batch = nPlanBatch1
+	nPlan.summarize(beta)
+	return nPlan, opt.Context.Err()
+}
+
+// SampleSchedule returns the group sizes at each step of a simulated experiment, in which the first group grows by one observation per step up to maxN.
+// At step i, the first group has n1[i] = i+1 observations, and the second group has n2[i] = ceil(ratio*n1[i]) observations.
+// This is the schedule of the simulations of GetNPlan with GetNPlanOptions.Ratio, which is useful for custom sequential simulations.
+func SampleSchedule(maxN int, ratio float64) (n1, n2 []int) {
+	for i := 1; i <= maxN; i++ {
+		n1 = append(n1, i)
+		n2 = append(n2, int(math.Ceil(ratio*float64(i))))
+	}
+	return n1, n2
+}
+
+// simulateNPlan simulates experiments with early stopping, whose first group grows up to n1Max observations, and returns their e-values and stopping times.
+// n2Max is the size of the second group in batch mode, which bounds the length of the simulated samples together with n1Max.
+// beta is the desired power used by ConvergenceTol.
+func simulateNPlan(alpha, beta float64, n1Max, n2Max int, opt GetNPlanOptions) NPlan {
+	p := opt.Mom
+	var nPlan NPlan
 
 	// Interpolate n1 and n2.
-	var n1Vector, n2Vector []int
-	for i := 1; i <= nPlanBatch1; i++ {
-		n1Vector = append(n1Vector, i)
-		n2Vector = append(n2Vector, int(math.Ceil(opt.Ratio*float64(i))))
+	n1Vector, n2Vector := SampleSchedule(n1Max, opt.Ratio)
+	if opt.N2 > 0 {
+		for i := range n2Vector {
+			n2Vector[i] = opt.N2
+		}
 	}
 
 	// Simulation experiments.
 	rnd := rand.New(opt.Rsrc)
-	sampleLen := max(nPlanBatch1, nPlanBatch2)
+	sampleLen := max(n1Max, n2Max)
+	// Rounding up n2 for every n1 may exceed the batch sample size of the second group.
+	if len(n2Vector) > 0 {
+		sampleLen = max(sampleLen, n2Vector[len(n2Vector)-1])
+	}
+	interpolate1 := newInterpolator(len(n1Vector), sampleLen)
+	interpolate2 := newInterpolator(len(n2Vector), sampleLen)
 	sample1, sample2 := make([]float64, sampleLen), make([]float64, sampleLen)
-	interpolate1 := newInterpolator(len(n1Vector), len(sample1))
-	interpolate2 := newInterpolator(len(n2Vector), len(sample2))
-	for range opt.NumSimulations {
+	var all, firstHalf stopTCounter
+	for sim := range opt.NumSimulations {
+		if opt.Context.Err() != nil {
+			break
+		}
+
 		// Generate simulation data.
-		for i := range sampleLen {
-			sample1[i] = deltaMin/2 + rnd.NormFloat64()
-			sample2[i] = -deltaMin/2 + rnd.NormFloat64()
+		if opt.Antithetic && sim%2 == 1 {
+			antithetic(opt.DataGen.(SymmetricGen), sample1, sample2)
+		} else {
+			generateInto(opt.DataGen, rnd, sample1, sample2)
 		}
 
 		// Interpolate between n1 and n2, so that the resulting slices are of the same length.
@@ -189,27 +792,299 @@ func GetNPlan(alpha, beta, deltaMin float64, options ...GetNPlanOptions) NPlan {
 
 		nPlan.EValue = append(nPlan.EValue, eValues)
 		nPlan.StopT = append(nPlan.StopT, stopT)
+		opt.Progress(len(nPlan.StopT), opt.NumSimulations)
+
+		// Stop early once N stabilizes.
+		// The stopping times of all simulations and of the first half are counted online, rather than sorted at every check.
+		all.add(stopT)
+		if opt.ConvergenceTol > 0 && len(nPlan.StopT)%convergenceCheckInterval == 0 {
+			for firstHalf.n < len(nPlan.StopT)/2 {
+				firstHalf.add(nPlan.StopT[firstHalf.n])
+			}
+			n := all.quantile(1 - beta)
+			half := firstHalf.quantile(1 - beta)
+			if math.Abs(float64(n-half)) <= opt.ConvergenceTol*math.Abs(float64(n)) {
+				break
+			}
+		}
+	}
+
+	return nPlan
+}
+
+// newGetNPlanOptions validates alpha, beta and the first of options, and returns the options with defaults filled in.
+func newGetNPlanOptions(alpha, beta, deltaMin float64, options []GetNPlanOptions) (GetNPlanOptions, error) {
+	if !validProbability(alpha) {
+		return GetNPlanOptions{}, fmt.Errorf("%w: alpha %f", ErrInvalidProbability, alpha)
+	}
+	if !validProbability(beta) {
+		return GetNPlanOptions{}, fmt.Errorf("%w: beta %f", ErrInvalidProbability, beta)
+	}
+	var opt GetNPlanOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if !(opt.Ratio >= 0) || math.IsInf(opt.Ratio, 1) {
+		return GetNPlanOptions{}, fmt.Errorf("%w: Ratio %f", ErrInvalidOptions, opt.Ratio)
+	}
+	if opt.NumSimulations < 0 {
+		return GetNPlanOptions{}, fmt.Errorf("%w: NumSimulations %d", ErrInvalidOptions, opt.NumSimulations)
+	}
+	if opt.N2 < 0 {
+		return GetNPlanOptions{}, fmt.Errorf("%w: N2 %d", ErrInvalidOptions, opt.N2)
+	}
+	if !(opt.ConvergenceTol >= 0) {
+		return GetNPlanOptions{}, fmt.Errorf("%w: ConvergenceTol %f", ErrInvalidOptions, opt.ConvergenceTol)
+	}
+	if opt.Ratio == 0 {
+		opt.Ratio = 1
+	}
+	if opt.NumSimulations == 0 {
+		opt.NumSimulations = 1000
+	}
+	if opt.Rsrc == nil {
+		opt.Rsrc = newDefaultRandSource()
+	}
+	if opt.DataGen == nil {
+		opt.DataGen = GaussianGen{Delta: deltaMin}
+	}
+	if _, ok := opt.DataGen.(SymmetricGen); opt.Antithetic && !ok {
+		return GetNPlanOptions{}, fmt.Errorf("%w: Antithetic with DataGen %T", ErrInvalidOptions, opt.DataGen)
+	}
+	if opt.Mom == nil {
+		opt.Mom = NewMom(deltaMin)
+	}
+	if opt.Context == nil {
+		opt.Context = context.Background()
+	}
+	if opt.Progress == nil {
+		opt.Progress = func(int, int) {}
+	}
+	return opt, nil
+}
+
+// ContinueNPlan refines prev, which is returned by GetNPlan with the same alpha, beta, deltaMin and options, by extraSamples more simulations.
+// The stopping times of the extra simulations are merged with those of prev, and N and Mean are recomputed on the combined set.
+// If options contain the random source of prev, which has since advanced, the result equals that of a single GetNPlan run with NumSimulations increased by extraSamples.
+// The result shares no memory with prev.
+func ContinueNPlan(prev NPlan, extraSamples int, alpha, beta, deltaMin float64, options ...GetNPlanOptions) NPlan {
+	nPlan := prev
+	nPlan.EValue = make([][]float64, 0, len(prev.EValue))
+	for _, e := range prev.EValue {
+		nPlan.EValue = append(nPlan.EValue, slices.Clone(e))
+	}
+	nPlan.StopT = slices.Clone(prev.StopT)
+	if extraSamples <= 0 || prev.Batch < 0 {
+		return nPlan
+	}
+	var opt GetNPlanOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	opt.NumSimulations = extraSamples
+	extra := GetNPlan(alpha, beta, deltaMin, opt)
+
+	nPlan.EValue = append(nPlan.EValue, extra.EValue...)
+	nPlan.StopT = append(nPlan.StopT, extra.StopT...)
+	nPlan.summarize(beta)
+	return nPlan
+}
+
+// summarize computes N and Mean from the stopping times, for the desired statistical power 1-beta.
+func (np *NPlan) summarize(beta float64) {
+	// No simulation completes if the context is cancelled from the start, in which case only the sample size in batch mode is known.
+	if len(np.StopT) == 0 {
+		return
 	}
 
 	// Compute sample size for the desired statistical power.
-	stopT := make([]float64, len(nPlan.StopT))
-	for i, t := range nPlan.StopT {
-		if t == notStopped {
-			stopT[i] = math.Inf(1)
-		} else {
-			stopT[i] = float64(t)
+	// The stopping times are counted rather than sorted, so that refining a large NPlan by ContinueNPlan avoids sorting all of them again.
+	var c stopTCounter
+	for _, t := range np.StopT {
+		c.add(t)
+	}
+	np.N = c.quantile(1 - beta)
+
+	// Calculate the average stopping time, assuming we go according to plan.
+	np.Mean = int(math.Ceil(c.truncatedMean(np.N)))
+}
+
+// RecommendNumSamples returns the NumSimulations of GetNPlan, at which the standard errors of both NPlan.N and NPlan.Mean are about targetMeanStdErr.
+// It runs a pilot simulation, and bootstraps the pilot stopping times to estimate the standard errors, which shrink with the square root of NumSimulations.
+// If the pilot simulation is cancelled, the standard errors are estimated from the pilot simulations completed so far.
+// It returns 0 if targetMeanStdErr is not positive, if alpha or beta does not lie in (0, 1), if the desired power cannot be reached,
+// or if no pilot simulation completes because the context is cancelled from the start.
+func RecommendNumSamples(targetMeanStdErr, alpha, beta, deltaMin float64, options ...SimulateOptions) int {
+	if !(targetMeanStdErr > 0) || !validProbability(alpha) || !validProbability(beta) {
+		return 0
+	}
+	opt := newSimulateOptions(options)
+	const numPilot = 200
+	const numBootstrap = 200
+	rnd := rand.New(opt.Rsrc)
+	pilot := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{NumSimulations: numPilot, Rsrc: rnd, Context: opt.Context, Progress: opt.Progress})
+	if pilot.Batch < 0 || len(pilot.StopT) == 0 {
+		return 0
+	}
+	stopT := sortedStopT(pilot.StopT)
+
+	resampled := make([]float64, len(stopT))
+	ns, means := make([]float64, numBootstrap), make([]float64, numBootstrap)
+	for b := range numBootstrap {
+		for i := range resampled {
+			resampled[i] = stopT[rnd.IntN(len(stopT))]
+		}
+		slices.Sort(resampled)
+		n := stopTQuantile(1-beta, resampled)
+		var sum float64
+		for _, t := range resampled {
+			sum += min(float64(n), t)
 		}
+		ns[b], means[b] = float64(n), sum/float64(len(resampled))
+	}
+
+	stdErr := max(stat.StdDev(ns, nil), stat.StdDev(means, nil))
+	ratio := stdErr / targetMeanStdErr
+	return max(1, int(math.Ceil(float64(len(stopT))*ratio*ratio)))
+}
+
+// Power returns the fraction of simulations which rejected the null hypothesis within the batch sample size.
+// It is at least one minus beta, since N is the stopping time that achieves this power.
+func (np NPlan) Power() float64 {
+	if len(np.StopT) == 0 {
+		return 0
+	}
+	var stopped int
+	for _, t := range np.StopT {
+		if t != notStopped {
+			stopped++
+		}
+	}
+	return float64(stopped) / float64(len(np.StopT))
+}
+
+// Validate reports whether the e-values and stopping times of np are consistent with each other at the significance level alpha, such as after deserializing np.
+// It returns an error wrapping ErrInvalidProbability if alpha does not lie in (0, 1), and an error if the numbers of trajectories and stopping times differ,
+// or if a stopping time is not the FirstCrossing of its e-value trajectory.
+func (np NPlan) Validate(alpha float64) error {
+	if !validProbability(alpha) {
+		return fmt.Errorf("%w: alpha %f", ErrInvalidProbability, alpha)
+	}
+	if len(np.EValue) != len(np.StopT) {
+		return fmt.Errorf("evalue: %d e-value trajectories but %d stopping times", len(np.EValue), len(np.StopT))
+	}
+	for i, eValues := range np.EValue {
+		if want := FirstCrossing(eValues, alpha); np.StopT[i] != want {
+			return fmt.Errorf("evalue: simulation %d: stopping time %d but first crossing %d", i, np.StopT[i], want)
+		}
+	}
+	return nil
+}
+
+// FirstCrossing returns the stopping time of the e-value trajectory eValues, which is the first step, counting from 1, whose e-value exceeds 1/alpha.
+// It returns -1 if the trajectory never exceeds 1/alpha.
+func FirstCrossing(eValues []float64, alpha float64) int {
+	i := slices.IndexFunc(eValues, func(e float64) bool { return e > 1./alpha })
+	if i < 0 {
+		return notStopped
+	}
+	return i + 1
+}
+
+// StopPercentiles returns the percentiles ps of the stopping times during simulation.
+// Each p in ps must lie in [0, 1].
+// A percentile that falls among the simulations which did not reject the null hypothesis is reported as -1.
+func (np NPlan) StopPercentiles(ps []float64) []int {
+	stopT := sortedStopT(np.StopT)
+	percentiles := make([]int, len(ps))
+	for i, p := range ps {
+		percentiles[i] = stopTQuantile(p, stopT)
+	}
+	return percentiles
+}
+
+// StopInterval returns the central interval of the stopping times of the simulations which rejected the null hypothesis, covering the fraction level of them.
+// For example, a level of 0.95 returns the 2.5th and 97.5th percentiles, stating that experiments are expected to stop between the two sample sizes.
+// It returns [-1, -1] if no simulation rejected the null hypothesis.
+func (np NPlan) StopInterval(level float64) [2]int {
+	var stopT []float64
+	for _, t := range np.StopT {
+		if t != notStopped {
+			stopT = append(stopT, float64(t))
+		}
+	}
+	if len(stopT) == 0 {
+		return [2]int{notStopped, notStopped}
 	}
 	slices.Sort(stopT)
-	nPlan.N = int(math.Ceil(stat.Quantile(1-beta, stat.LinInterp, stopT, nil)))
+	tail := (1 - level) / 2
+	return [2]int{stopTQuantile(tail, stopT), stopTQuantile(1-tail, stopT)}
+}
 
-	// Calculate the average stopping time, assuming we go according to plan.
-	for i := range stopT {
-		stopT[i] = min(float64(nPlan.N), stopT[i])
+// WriteCSV writes the e-values during simulation to w in CSV format, one row per simulation step.
+// The columns are the simulation index, the step which is the sample size of the first group, the e-value, and the stopping time of the simulation.
+func (np NPlan) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	row := []string{"sample", "step", "evalue", "stopT"}
+	if err := cw.Write(row); err != nil {
+		return err
 	}
-	nPlan.Mean = int(math.Ceil(stat.Mean(stopT, nil)))
+	for i, eValues := range np.EValue {
+		for j, e := range eValues {
+			row[0] = strconv.Itoa(i)
+			row[1] = strconv.Itoa(j + 1)
+			row[2] = strconv.FormatFloat(e, 'f', -1, 64)
+			row[3] = strconv.Itoa(np.StopT[i])
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
 
-	return nPlan
+// LogEValueTrajectories returns the base-10 logarithms of the e-values during simulation.
+// E-values span many orders of magnitude, so their logarithms are friendlier for plotting, with the warm-up e-value of 1 at 0.
+// Overflowed or underflowed e-values are clamped to the logarithms of the largest and smallest positive float64, so that all values are finite.
+func (np NPlan) LogEValueTrajectories() [][]float64 {
+	logMax, logMin := math.Log10(math.MaxFloat64), math.Log10(math.SmallestNonzeroFloat64)
+	trajectories := make([][]float64, len(np.EValue))
+	for i, eValues := range np.EValue {
+		trajectories[i] = make([]float64, len(eValues))
+		for j, e := range eValues {
+			trajectories[i][j] = min(max(math.Log10(e), logMin), logMax)
+		}
+	}
+	return trajectories
+}
+
+// sortedStopT returns the stopping times in increasing order, with notStopped replaced by infinity.
+func sortedStopT(stopT []int) []float64 {
+	sorted := make([]float64, len(stopT))
+	for i, t := range stopT {
+		if t == notStopped {
+			sorted[i] = math.Inf(1)
+		} else {
+			sorted[i] = float64(t)
+		}
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+// stopTQuantile returns the p-quantile of the sorted stopping times, rounded up.
+// It returns notStopped if the quantile is infinite.
+func stopTQuantile(p float64, stopT []float64) int {
+	q := stat.Quantile(p, stat.LinInterp, stopT, nil)
+	// Linear interpolation towards infinity yields NaN when the weight of the infinite end is zero.
+	if math.IsNaN(q) {
+		q = stat.Quantile(p, stat.Empirical, stopT, nil)
+	}
+	if math.IsInf(q, 1) {
+		return notStopped
+	}
+	return int(math.Ceil(q))
 }
 
 // TStatistic holds information about a t-statistic.
@@ -228,16 +1103,65 @@ type TStatistic struct {
 	T float64
 }
 
+// sumSqDev returns the sum of squared deviations of x from its mean, which is 0 for a single observation.
+func sumSqDev(x []float64) float64 {
+	if len(x) < 2 {
+		return 0
+	}
+	return float64(len(x)-1) * stat.Variance(x, nil)
+}
+
+// prefixMoments returns the mean and the sum of squared deviations from the mean of each prefix x[:i+1].
+// Unlike cumulative sums of squares, Welford's updates do not cancel catastrophically when the mean is large relative to the spread,
+// and keep the squared deviations of constant observations at exactly zero.
+func prefixMoments(x []float64) (means, m2s []float64) {
+	means, m2s = make([]float64, len(x)), make([]float64, len(x))
+	var mean, m2 float64
+	for i, v := range x {
+		d := v - mean
+		mean += d / float64(i+1)
+		m2 += d * (v - mean)
+		means[i], m2s[i] = mean, m2
+	}
+	return means, m2s
+}
+
+// sufficient reports whether there are enough observations to estimate the variance.
+func (t TStatistic) sufficient() bool {
+	return t.Nu > 0 && t.NEff > 0
+}
+
+// defined reports whether the t-statistic is defined.
+// Constant groups, whose T is 0/0, are considered defined, and carry no evidence, see EValuePhi0.
+func (t TStatistic) defined() bool {
+	return t.Nu > 0 && (!math.IsNaN(t.T) || t.Sp == 0)
+}
+
+// EffectiveSampleSize returns the effective sample size n1*n2/(n1+n2) of two groups of sizes n1 and n2.
+// It is the size of a one sample test, whose standard error of the mean equals that of the difference between the group means.
+// It equals n/2 for equal group sizes n, and approaches the smaller group size as the other group grows.
+func EffectiveSampleSize(n1, n2 int) float64 {
+	m1, m2 := float64(n1), float64(n2)
+	return m1 * m2 / (m1 + m2)
+}
+
+// PooledVariance returns the pooled variance of the two groups x1 and x2, which is the sum of their squared deviations from the group means divided by the degree of freedom len(x1)+len(x2)-2.
+// Its square root is TStatistic.Sp.
+func PooledVariance(x1, x2 []float64) float64 {
+	nu := float64(len(x1) + len(x2) - 2)
+	return (sumSqDev(x1) + sumSqDev(x2)) / nu
+}
+
 // TStat returns the two sample t-statistic.
 // See equation 1 in Ly for more details.
 func TStat(x1, x2 []float64, phi0 float64) TStatistic {
 	n1, n2 := float64(len(x1)), float64(len(x2))
 	nu := n1 + n2 - 2
-	nEff := n1 * n2 / (n1 + n2)
+	nEff := EffectiveSampleSize(len(x1), len(x2))
 	mean1 := stat.Mean(x1, nil)
 	mean2 := stat.Mean(x2, nil)
 
-	sp := math.Sqrt(1. / nu * ((n1-1)*stat.Variance(x1, nil) + (n2-1)*stat.Variance(x2, nil)))
+	sp := math.Sqrt(PooledVariance(x1, x2))
 	t := math.Sqrt(nEff) * (mean1 - mean2 - phi0) / sp
 
 	ts := TStatistic{
@@ -251,26 +1175,38 @@ func TStat(x1, x2 []float64, phi0 float64) TStatistic {
 	return ts
 }
 
+// newDefaultRandSource returns the random source used by simulations when none is provided.
+func newDefaultRandSource() rand.Source {
+	return rand.NewChaCha8([32]byte{0x01, 0x08, 0x02, 0x08, 0x83, 0x15, 0x07, 0x19, 0x64, 0x7a, 0x64, 0x5f, 0x71, 0x7e, 0x07, 0x01, 0xd9, 0x80, 0x61, 0xed, 0xce, 0xaa, 0x4e, 0xf2, 0x2f, 0x36, 0xb5, 0x18, 0x82, 0x85, 0x07, 0x01})
+}
+
+// batchSizes returns the sizes of the two groups without early stopping, which are -1 if the desired power cannot be reached.
+func batchSizes(alpha, beta, deltaMin float64, opt GetNPlanOptions) (int, int) {
+	if opt.N2 > 0 {
+		return getNPlanBatchN2(alpha, beta, deltaMin, opt.N2, opt.Mom), opt.N2
+	}
+	return getNPlanBatch(alpha, beta, deltaMin, opt.Ratio, opt.Mom)
+}
+
 func getNPlanBatch(alpha, beta, delta, ratio float64, p *Mom) (int, int) {
+	// Without an effect, the power never exceeds alpha.
+	if delta == 0 {
+		return -1, -1
+	}
+
 	// Define the function f that returns eValue - 1/alpha, given nEff.
 	delta = math.Abs(delta)
 	f := func(nEff float64) float64 {
 		nu := math.Pow(1+ratio, 2)/ratio*nEff - 2
-		t := distuv.NoncentralT{Nu: nu, Mu: math.Sqrt(nEff) * delta}.Quantile(beta)
+		t := distuv.NoncentralT{Nu: nu, Mu: Noncentrality(nEff, delta)}.Quantile(beta)
 		s := p.eValue(t, nu, nEff)
 		return s - 1./alpha
 	}
 
-	// Solve for the root of f.
-	//
-	// Find the bracket that wraps the root.
-	qB := distuv.Normal{Sigma: 1}.Quantile(beta)
-	guess := 2 / (delta * delta) * (qB*qB - qB*math.Sqrt(qB*qB+2*math.Log(1./alpha)) + math.Log(1./alpha))
-	a, b := findBracketMono(f, guess)
-	// Find the root inside the bracket.
+	// Solve for the root of f, starting from the normal approximation of nEff.
 	eps := math.Nextafter(1, 2) - 1
 	tol := math.Pow(eps, 0.25)
-	nEff, err := root.Brent(f, a, b, tol)
+	nEff, err := solveBrentMono(f, batchNEffGuess(alpha, beta, delta), tol)
 	if err != nil {
 		return -1, -1
 	}
@@ -280,6 +1216,51 @@ func getNPlanBatch(alpha, beta, delta, ratio float64, p *Mom) (int, int) {
 	return n1, n2
 }
 
+// getNPlanBatchN2 returns the sample size of the first group without early stopping, when the second group has the fixed size n2.
+// It returns -1 if the desired power cannot be reached by growing the first group alone.
+func getNPlanBatchN2(alpha, beta, delta float64, n2 int, p *Mom) int {
+	// Define the function f that returns eValue - 1/alpha, given n1.
+	delta = math.Abs(delta)
+	m := float64(n2)
+	f := func(n1 float64) float64 {
+		nu := n1 + m - 2
+		nEff := n1 * m / (n1 + m)
+		t := distuv.NoncentralT{Nu: nu, Mu: Noncentrality(nEff, delta)}.Quantile(beta)
+		s := p.eValue(t, nu, nEff)
+		return s - 1./alpha
+	}
+
+	// Since nEff is bounded by n2, the power is bounded as well, and so we give up if it is not reached by a finite n1.
+	a := math.Max(1, 3-m)
+	if f(a) >= 0 {
+		return int(math.Ceil(a))
+	}
+	const maxGrowth = 1 << 30
+	if f(a*maxGrowth) < 0 {
+		return -1
+	}
+
+	// Solve for the root of f above a, where f is defined, starting from the n1 of the normal approximation of nEff.
+	// The e-value at the beta quantile of t is not monotone for tiny n1, and so the guess must not be too small.
+	guess := a * maxGrowth / 2
+	if nEff := batchNEffGuess(alpha, beta, delta); nEff < m {
+		guess = max(a, nEff*m/(m-nEff)-a)
+	}
+	eps := math.Nextafter(1, 2) - 1
+	tol := math.Pow(eps, 0.25)
+	dn1, err := solveBrentMono(func(dn1 float64) float64 { return f(a + dn1) }, guess, tol)
+	if err != nil {
+		return -1
+	}
+	return int(math.Ceil(a + dn1))
+}
+
+// batchNEffGuess returns the effective sample size without early stopping, under the normal approximation of the t-statistic.
+func batchNEffGuess(alpha, beta, delta float64) float64 {
+	qB := distuv.Normal{Sigma: 1}.Quantile(beta)
+	return 2 / (delta * delta) * (qB*qB - qB*math.Sqrt(qB*qB+2*math.Log(1./alpha)) + math.Log(1./alpha))
+}
+
 // interpolator holds buffers for interpolating between two groups of data of different sizes.
 type interpolator struct {
 	x    []float64
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"cmp"
 	_ "embed"
+	"encoding/binary"
 	"encoding/csv"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"math"
+	"math/rand/v2"
+	"reflect"
 	"slices"
 	"strconv"
 	"testing"
 
 	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
 	"gonum.org/v1/gonum/stat/distuv"
 )
 
@@ -51,6 +57,10 @@ func TestSaviTutorial_1_1(t *testing.T) {
 			if !(len(x) > 1 && len(y) > 1) {
 				continue
 			}
+			// The tutorial draws no conclusion while both groups are constant.
+			if TStat(x, y, 0).Sp == 0 {
+				continue
+			}
 
 			p := NewMom(0.769)
 			tt.e = p.EValue(x, y)
@@ -221,8 +231,7 @@ func TestEValue(t *testing.T) {
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
 			t.Parallel()
-			data = data[:test.n]
-			x, y := splitGray(data)
+			x, y := splitGray(data[:test.n])
 			if !(len(x) > 1 && len(y) > 1) {
 				return
 			}
@@ -236,78 +245,173 @@ func TestEValue(t *testing.T) {
 	}
 }
 
-func TestEValueT(t *testing.T) {
+func TestEValueConstant(t *testing.T) {
 	t.Parallel()
+	const alpha = 0.05
 	tests := []struct {
-		t    float64
-		n1   int
-		n2   int
-		want float64
+		x    []float64
+		y    []float64
+		phi0 float64
+	}{
+		{x: []float64{3, 3}, y: []float64{3, 3}, phi0: 0},
+		{x: []float64{3, 3, 3}, y: []float64{1, 1, 1}, phi0: 2},
+		{x: []float64{3, 3}, y: []float64{5, 5}, phi0: 0},
+		{x: []float64{3, 3}, y: []float64{3, 3}, phi0: 1},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			p := &Mom{G: 0.1339827}
+			// Constant groups carry no evidence.
+			if e := p.EValuePhi0(test.x, test.y, test.phi0); e != 1 {
+				t.Errorf("unexpected result EValuePhi0(%v, %v, %f): got %f want 1", test.x, test.y, test.phi0, e)
+			}
+			if e := p.LogEValue(test.x, test.y); e != 0 {
+				t.Errorf("unexpected result LogEValue(%v, %v): got %f want 0", test.x, test.y, e)
+			}
+
+			// The confidence interval excludes no phi0, whose e-value is less than 1/alpha.
+			ci := p.CI(test.x, test.y, alpha)
+			if want := [2]float64{math.Inf(-1), math.Inf(1)}; ci != want {
+				t.Errorf("unexpected CI(%v, %v): got %v want %v", test.x, test.y, ci, want)
+			}
+			if CIExcludes(ci, test.phi0) {
+				t.Errorf("CI %v excludes %f", ci, test.phi0)
+			}
+
+			// The streaming e-value agrees.
+			s := NewMomStream(p)
+			for _, v := range test.x {
+				s.Push(1, v)
+			}
+			for _, v := range test.y {
+				s.Push(2, v)
+			}
+			if e, want := s.EValue(), p.EValue(test.x, test.y); e != want {
+				t.Errorf("unexpected MomStream e-value: got %f want %f", e, want)
+			}
+		})
+	}
+}
+
+func TestEValueWindow(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	tests := []struct {
+		window int
+		want   float64
 	}{
-		{t: 0, n1: 2, n2: 2, want: 0.8281145},
-		{t: 0.3429972, n1: 3, n2: 3, want: 0.7874301},
-		{t: 0.3429972, n1: 4, n2: 4, want: 0.7307366},
-		{t: 1.073751, n1: 5, n2: 5, want: 0.9691936},
-		{t: 1.431668, n1: 6, n2: 5, want: 1.230795},
-		{t: 1.550761, n1: 7, n2: 6, want: 1.374565},
-		{t: 1.94993, n1: 8, n2: 7, want: 2.019262},
-		{t: 2.244057, n1: 9, n2: 8, want: 2.8438},
-		{t: 2.169469, n1: 10, n2: 9, want: 2.802736},
-		{t: 2.251096, n1: 11, n2: 10, want: 3.241674},
-		{t: 2.499726, n1: 12, n2: 10, want: 4.47004},
-		{t: 2.530404, n1: 13, n2: 11, want: 4.960147},
-		{t: 2.94071, n1: 14, n2: 12, want: 9.103413},
-		{t: 3.0295, n1: 15, n2: 13, want: 11.1969},
-		{t: 3.520671, n1: 16, n2: 14, want: 24.76002},
-		{t: 3.332526, n1: 17, n2: 15, want: 20.80801},
-		{t: 3.479162, n1: 18, n2: 15, want: 27.2232},
-		{t: 3.202694, n1: 19, n2: 16, want: 19.07348},
-		{t: 3.292121, n1: 20, n2: 17, want: 23.78914},
-		{t: 3.086168, n1: 21, n2: 18, want: 17.69949},
-		{t: 3.341982, n1: 22, n2: 19, want: 29.70011},
-		{t: 3.398061, n1: 23, n2: 20, want: 35.06838},
-		{t: 3.501578, n1: 24, n2: 20, want: 43.9315},
-		{t: 3.43804, n1: 25, n2: 21, want: 41.25348},
-		{t: 3.542218, n1: 26, n2: 22, want: 53.8048},
-		{t: 3.376091, n1: 27, n2: 23, want: 40.28666},
-		{t: 3.110812, n1: 28, n2: 24, want: 24.09196},
-		{t: 2.991821, n1: 29, n2: 25, want: 19.2767},
-		{t: 3.000492, n1: 30, n2: 25, want: 19.83799},
-		{t: 3.061378, n1: 31, n2: 26, want: 23.12938},
-		{t: 3.17577, n1: 32, n2: 27, want: 30.46774},
-		{t: 3.238277, n1: 33, n2: 28, want: 35.95707},
-		{t: 3.229884, n1: 34, n2: 29, want: 36.11804},
-		{t: 3.222106, n1: 35, n2: 30, want: 36.2442},
-		{t: 3.034851, n1: 36, n2: 30, want: 23.6237},
-		{t: 3.24621, n1: 37, n2: 31, want: 39.49257},
-		{t: 3.305981, n1: 38, n2: 32, want: 46.50529},
-		{t: 3.499248, n1: 39, n2: 33, want: 76.64593},
-		{t: 3.683814, n1: 40, n2: 34, want: 125.8569},
-		{t: 3.780777, n1: 41, n2: 35, want: 166.7825},
-		{t: 3.900473, n1: 42, n2: 35, want: 232.4453},
-		{t: 4.214193, n1: 43, n2: 36, want: 567.0516},
-		{t: 4.497879, n1: 44, n2: 37, want: 1319.02},
-		{t: 4.590277, n1: 45, n2: 38, want: 1809.453},
-		{t: 4.843779, n1: 46, n2: 39, want: 4034.909},
-		{t: 4.667405, n1: 47, n2: 40, want: 2507.086},
-		{t: 4.777502, n1: 48, n2: 40, want: 3574.364},
-		{t: 4.762545, n1: 49, n2: 41, want: 3589.371},
-		{t: 4.931264, n1: 50, n2: 42, want: 6368.81},
-		{t: 5.157969, n1: 51, n2: 43, want: 13809.68},
-		{t: 5.247082, n1: 52, n2: 44, want: 19532.6},
-		{t: 5.318573, n1: 53, n2: 45, want: 26205.62},
-		{t: 5.383296, n1: 54, n2: 45, want: 33386.48},
-		{t: 5.564101, n1: 55, n2: 46, want: 65342.26},
-		{t: 5.533735, n1: 56, n2: 47, want: 62754.71},
-		{t: 5.76241, n1: 57, n2: 48, want: 147884.5},
-		{t: 5.820789, n1: 58, n2: 49, want: 194347.9},
-		{t: 5.897718, n1: 59, n2: 50, want: 273684.7},
-		{t: 6.000207, n1: 60, n2: 50, want: 408974.1},
-		{t: 6.11447, n1: 61, n2: 51, want: 666879},
-		{t: 5.917839, n1: 62, n2: 52, want: 345933.3},
-		{t: 5.899927, n1: 63, n2: 53, want: 344469.9},
-		{t: 5.976485, n1: 64, n2: 54, want: 487417.9},
+		{window: len(data), want: (&Mom{G: 0.1339827}).EValue(x, y)},
+		{window: max(len(x), len(y)), want: (&Mom{G: 0.1339827}).EValue(x, y)},
+		{window: 30, want: (&Mom{G: 0.1339827}).EValue(x[len(x)-30:], y[len(y)-30:])},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			p := &Mom{G: 0.1339827}
+			s := p.EValueWindow(x, y, test.window)
+			if s != test.want {
+				t.Errorf("unexpected result EValueWindow(%d): got %f want %f", test.window, s, test.want)
+			}
+		})
+	}
+}
+
+func TestEValueCapped(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	const alpha = 0.05
+	p := &Mom{G: 0.1339827}
+	for _, cap := range []float64{1, 10, 1000} {
+		for n := 4; n <= len(data); n++ {
+			x, y := splitGray(data[:n])
+			e, capped := p.EValue(x, y), p.EValueCapped(x, y, cap)
+			if capped > cap || capped > e {
+				t.Errorf("EValueCapped(data[:%d], %f) = %f exceeds the cap or the e-value %f", n, cap, capped, e)
+			}
+			if decision := capped > 1./alpha; cap > 1./alpha && decision != (e > 1./alpha) {
+				t.Errorf("EValueCapped(data[:%d], %f) = %f changes the decision of e-value %f", n, cap, capped, e)
+			}
+		}
 	}
+}
+
+// eValueTTests are reference e-values along a trajectory of t-statistics, for the mom e-process with G=0.1339827.
+var eValueTTests = []struct {
+	t    float64
+	n1   int
+	n2   int
+	want float64
+}{
+	{t: 0, n1: 2, n2: 2, want: 0.8281145},
+	{t: 0.3429972, n1: 3, n2: 3, want: 0.7874301},
+	{t: 0.3429972, n1: 4, n2: 4, want: 0.7307366},
+	{t: 1.073751, n1: 5, n2: 5, want: 0.9691936},
+	{t: 1.431668, n1: 6, n2: 5, want: 1.230795},
+	{t: 1.550761, n1: 7, n2: 6, want: 1.374565},
+	{t: 1.94993, n1: 8, n2: 7, want: 2.019262},
+	{t: 2.244057, n1: 9, n2: 8, want: 2.8438},
+	{t: 2.169469, n1: 10, n2: 9, want: 2.802736},
+	{t: 2.251096, n1: 11, n2: 10, want: 3.241674},
+	{t: 2.499726, n1: 12, n2: 10, want: 4.47004},
+	{t: 2.530404, n1: 13, n2: 11, want: 4.960147},
+	{t: 2.94071, n1: 14, n2: 12, want: 9.103413},
+	{t: 3.0295, n1: 15, n2: 13, want: 11.1969},
+	{t: 3.520671, n1: 16, n2: 14, want: 24.76002},
+	{t: 3.332526, n1: 17, n2: 15, want: 20.80801},
+	{t: 3.479162, n1: 18, n2: 15, want: 27.2232},
+	{t: 3.202694, n1: 19, n2: 16, want: 19.07348},
+	{t: 3.292121, n1: 20, n2: 17, want: 23.78914},
+	{t: 3.086168, n1: 21, n2: 18, want: 17.69949},
+	{t: 3.341982, n1: 22, n2: 19, want: 29.70011},
+	{t: 3.398061, n1: 23, n2: 20, want: 35.06838},
+	{t: 3.501578, n1: 24, n2: 20, want: 43.9315},
+	{t: 3.43804, n1: 25, n2: 21, want: 41.25348},
+	{t: 3.542218, n1: 26, n2: 22, want: 53.8048},
+	{t: 3.376091, n1: 27, n2: 23, want: 40.28666},
+	{t: 3.110812, n1: 28, n2: 24, want: 24.09196},
+	{t: 2.991821, n1: 29, n2: 25, want: 19.2767},
+	{t: 3.000492, n1: 30, n2: 25, want: 19.83799},
+	{t: 3.061378, n1: 31, n2: 26, want: 23.12938},
+	{t: 3.17577, n1: 32, n2: 27, want: 30.46774},
+	{t: 3.238277, n1: 33, n2: 28, want: 35.95707},
+	{t: 3.229884, n1: 34, n2: 29, want: 36.11804},
+	{t: 3.222106, n1: 35, n2: 30, want: 36.2442},
+	{t: 3.034851, n1: 36, n2: 30, want: 23.6237},
+	{t: 3.24621, n1: 37, n2: 31, want: 39.49257},
+	{t: 3.305981, n1: 38, n2: 32, want: 46.50529},
+	{t: 3.499248, n1: 39, n2: 33, want: 76.64593},
+	{t: 3.683814, n1: 40, n2: 34, want: 125.8569},
+	{t: 3.780777, n1: 41, n2: 35, want: 166.7825},
+	{t: 3.900473, n1: 42, n2: 35, want: 232.4453},
+	{t: 4.214193, n1: 43, n2: 36, want: 567.0516},
+	{t: 4.497879, n1: 44, n2: 37, want: 1319.02},
+	{t: 4.590277, n1: 45, n2: 38, want: 1809.453},
+	{t: 4.843779, n1: 46, n2: 39, want: 4034.909},
+	{t: 4.667405, n1: 47, n2: 40, want: 2507.086},
+	{t: 4.777502, n1: 48, n2: 40, want: 3574.364},
+	{t: 4.762545, n1: 49, n2: 41, want: 3589.371},
+	{t: 4.931264, n1: 50, n2: 42, want: 6368.81},
+	{t: 5.157969, n1: 51, n2: 43, want: 13809.68},
+	{t: 5.247082, n1: 52, n2: 44, want: 19532.6},
+	{t: 5.318573, n1: 53, n2: 45, want: 26205.62},
+	{t: 5.383296, n1: 54, n2: 45, want: 33386.48},
+	{t: 5.564101, n1: 55, n2: 46, want: 65342.26},
+	{t: 5.533735, n1: 56, n2: 47, want: 62754.71},
+	{t: 5.76241, n1: 57, n2: 48, want: 147884.5},
+	{t: 5.820789, n1: 58, n2: 49, want: 194347.9},
+	{t: 5.897718, n1: 59, n2: 50, want: 273684.7},
+	{t: 6.000207, n1: 60, n2: 50, want: 408974.1},
+	{t: 6.11447, n1: 61, n2: 51, want: 666879},
+	{t: 5.917839, n1: 62, n2: 52, want: 345933.3},
+	{t: 5.899927, n1: 63, n2: 53, want: 344469.9},
+	{t: 5.976485, n1: 64, n2: 54, want: 487417.9},
+}
+
+func TestEValueT(t *testing.T) {
+	t.Parallel()
+	tests := eValueTTests
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
 			t.Parallel()
@@ -323,6 +427,477 @@ func TestEValueT(t *testing.T) {
 	}
 }
 
+// TestEValueInto is not parallel, since testing.AllocsPerRun is not allowed in parallel tests.
+func TestEValueInto(t *testing.T) {
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := NewMom(0.5176537)
+	var scratch TStatistic
+	for n := 2; n <= min(len(x), len(y)); n++ {
+		if e, want := p.EValueInto(x[:n], y[:n], &scratch), p.EValue(x[:n], y[:n]); e != want {
+			t.Errorf("n %d: got %f want %f", n, e, want)
+		}
+		if scratch != TStat(x[:n], y[:n], 0) {
+			t.Errorf("n %d: unexpected scratch %+v", n, scratch)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { p.EValueInto(x, y, &scratch) })
+	if allocs != 0 {
+		t.Errorf("got %f allocations want 0", allocs)
+	}
+	// EValue itself does not allocate.
+	if allocs := testing.AllocsPerRun(100, func() { p.EValue(x, y) }); allocs != 0 {
+		t.Errorf("got %f allocations of EValue want 0", allocs)
+	}
+}
+
+func TestEValueFromTStream(t *testing.T) {
+	t.Parallel()
+	var ts, nus, nEffs []float64
+	for _, test := range eValueTTests {
+		ts = append(ts, test.t)
+		nus = append(nus, float64(test.n1+test.n2-2))
+		nEffs = append(nEffs, EffectiveSampleSize(test.n1, test.n2))
+	}
+	p := &Mom{G: 0.1339827}
+	eValues := p.EValueFromTStream(ts, nus, nEffs)
+	if len(eValues) != len(eValueTTests) {
+		t.Fatalf("got %d e-values want %d", len(eValues), len(eValueTTests))
+	}
+	for i, test := range eValueTTests {
+		if !scalar.EqualWithinRel(eValues[i], test.want, 2e-6) {
+			t.Errorf("%d: got %f want %f", i, eValues[i], test.want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("mismatched lengths do not panic")
+		}
+	}()
+	p.EValueFromTStream(ts, nus[1:], nEffs)
+}
+
+func TestEValueAtCounts(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := &Mom{G: 0.1339827}
+	counts := []int{0, 1, 2, 3, 7, 15, 16, 40, 17, min(len(x), len(y))}
+	eValues := p.EValueAtCounts(x, y, counts)
+	for i, c := range counts {
+		if want := p.EValue(x[:c], y[:c]); !scalar.EqualWithinRel(eValues[i], want, 1e-9) {
+			t.Errorf("count %d: got %f want %f", c, eValues[i], want)
+		}
+	}
+
+	// Constant groups are handled like EValue.
+	constant := []float64{3, 3, 3, 3}
+	for i, e := range p.EValueAtCounts(constant, []float64{4, 4, 4, 4}, []int{2, 4}) {
+		if e != 1 {
+			t.Errorf("%d: got %f want 1", i, e)
+		}
+	}
+	if e := p.EValueAtCounts(constant, constant, []int{4}); e[0] != p.EValue(constant, constant) {
+		t.Errorf("got %f want %f", e[0], p.EValue(constant, constant))
+	}
+	if e := p.EValueAtCounts(nil, nil, []int{0}); e[0] != 1 {
+		t.Errorf("got %f want 1", e[0])
+	}
+}
+
+func TestEValueAtCountsLargeOffset(t *testing.T) {
+	t.Parallel()
+	const alpha, offset = 0.05, 1e6
+	p := NewMom(0.5176537)
+	counts := make([]int, 0, 100)
+	for c := 2; c <= 100; c++ {
+		counts = append(counts, c)
+	}
+
+	// Monitor null data far from zero continuously.
+	const numSamples = 200
+	var rejected, rejectedEValue int
+	for i := range numSamples {
+		rnd := rand.New(rand.NewPCG(uint64(i), 1))
+		x, y := GaussianGen{}.Generate(rnd, 100)
+		for j := range x {
+			x[j] += offset
+			y[j] += offset
+		}
+
+		eValues := p.EValueAtCounts(x, y, counts)
+		for j, c := range counts {
+			if want := p.EValue(x[:c], y[:c]); !scalar.EqualWithinRel(eValues[j], want, 1e-6) {
+				t.Fatalf("%d count %d: got %f want %f", i, c, eValues[j], want)
+			}
+		}
+		if FirstCrossing(eValues, alpha) != notStopped {
+			rejected++
+		}
+		if slices.ContainsFunc(counts, func(c int) bool { return p.EValue(x[:c], y[:c]) > 1./alpha }) {
+			rejectedEValue++
+		}
+	}
+	if rejected != rejectedEValue || !(float64(rejected)/numSamples <= alpha) {
+		t.Errorf("got %d rejections of %d want %d, at most %f", rejected, numSamples, rejectedEValue, alpha*numSamples)
+	}
+}
+
+func TestMinRejectAlpha(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := NewMom(0.5176537)
+	for n := 1; n <= min(len(x), len(y)); n++ {
+		minAlpha := p.MinRejectAlpha(x[:n], y[:n])
+		if !(0 < minAlpha && minAlpha <= 1) {
+			t.Fatalf("%d: minimal alpha %f outside (0, 1]", n, minAlpha)
+		}
+		for _, alpha := range []float64{0.001, 0.01, 0.05, 0.1, 0.5} {
+			if reject := p.EValue(x[:n], y[:n]) > 1/alpha; reject != (minAlpha < alpha) {
+				t.Errorf("%d: rejection at alpha %f is %t but minimal alpha is %f", n, alpha, reject, minAlpha)
+			}
+		}
+	}
+	if minAlpha := p.MinRejectAlpha(x[:1], y[:1]); minAlpha != 1 {
+		t.Errorf("got %f want 1", minAlpha)
+	}
+
+	// An undefined e-value yields an undefined minimal alpha.
+	if minAlpha := p.MinRejectAlpha([]float64{1, math.NaN()}, []float64{2, 3}); !math.IsNaN(minAlpha) {
+		t.Errorf("got %f want NaN", minAlpha)
+	}
+}
+
+func TestLabelSwap(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := NewMom(0.5176537)
+	const alpha = 0.05
+	for _, n := range []int{2, 5, 15, 30, min(len(x), len(y))} {
+		x, y := x[:n], y[:n]
+		if e, swapped := p.EValue(x, y), p.EValue(y, x); e != swapped {
+			t.Errorf("%d: swapping groups changes the e-value from %f to %f", n, e, swapped)
+		}
+		if e, swapped := p.EValuePhi0(x, y, 0.3), p.EValuePhi0(y, x, -0.3); e != swapped {
+			t.Errorf("%d: swapping groups changes the e-value at phi0 from %f to %f", n, e, swapped)
+		}
+		ci, swapped := p.CI(x, y, alpha), p.CI(y, x, alpha)
+		if swapped != [2]float64{-ci[1], -ci[0]} {
+			t.Errorf("%d: swapped interval %v does not mirror %v", n, swapped, ci)
+		}
+		d, swappedD := p.CIEffectSize(x, y, alpha), p.CIEffectSize(y, x, alpha)
+		if swappedD != [2]float64{-d[1], -d[0]} {
+			t.Errorf("%d: swapped effect size interval %v does not mirror %v", n, swappedD, d)
+		}
+	}
+}
+
+func TestEValueG(t *testing.T) {
+	t.Parallel()
+	for _, g := range []float64{0.01, 0.1339827, 1} {
+		p := &Mom{G: g}
+		for _, x := range []float64{0, 0.5, 2.244057, 5.976485} {
+			for _, n := range [][2]float64{{2, 2}, {9, 8}, {64, 54}} {
+				nu, nEff := n[0]+n[1]-2, n[0]*n[1]/(n[0]+n[1])
+				if e, want := eValueG(x, nu, nEff, g), p.eValue(x, nu, nEff); e != want {
+					t.Errorf("eValueG(%f, %f, %f, %f): got %f want %f", x, nu, nEff, g, e, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEValueTooFewObservations(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		x []float64
+		y []float64
+	}{
+		{x: []float64{1}, y: []float64{2}},
+		{x: []float64{1}, y: []float64{1}},
+		{x: nil, y: []float64{1, 2, 3}},
+		{x: []float64{1, 2}, y: nil},
+		{x: nil, y: nil},
+	}
+	p := &Mom{G: 0.1339827}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if e := p.EValue(test.x, test.y); e != 1 {
+				t.Errorf("EValue = %f, want 1", e)
+			}
+			if e := p.EValueFromTStat(TStat(test.x, test.y, 0)); e != 1 {
+				t.Errorf("EValueFromTStat = %f, want 1", e)
+			}
+			if ci := p.CI(test.x, test.y, 0.05); !math.IsInf(ci[0], -1) || !math.IsInf(ci[1], 1) {
+				t.Errorf("CI = %v, want [-Inf, +Inf]", ci)
+			}
+			if ci := p.CIEffectSize(test.x, test.y, 0.05); !math.IsInf(ci[0], -1) || !math.IsInf(ci[1], 1) {
+				t.Errorf("CIEffectSize = %v, want [-Inf, +Inf]", ci)
+			}
+		})
+	}
+}
+
+func TestEValueFromTStat(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	p := &Mom{G: 0.1339827}
+	for _, n := range []int{10, 30, len(data)} {
+		x, y := splitGray(data[:n])
+		if e, want := p.EValueFromTStat(TStat(x, y, 0)), p.EValue(x, y); e != want {
+			t.Errorf("EValueFromTStat(data[:%d]) = %f, want %f", n, e, want)
+		}
+	}
+}
+
+func TestEValueHypergeoOverflow(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		t    float64
+		nu   float64
+		nEff float64
+	}{
+		// The hypergeometric function overflows, but the e-value does not.
+		{t: 58.97, nu: 1000, nEff: 250.5},
+		{t: 38.91, nu: 10000, nEff: 2500.5},
+		// The e-value overflows too.
+		{t: 1e6, nu: 1998, nEff: 500},
+	}
+	g := 0.1339827
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			want, _ := eValueBig(g, test.t, test.nu, test.nEff, 64).Float64()
+			if e := eValueG(test.t, test.nu, test.nEff, g); !(e == want || scalar.EqualWithinRel(e, want, 1e-9)) {
+				t.Errorf("got %g want %g", e, want)
+			}
+		})
+	}
+}
+
+func TestEValueOverflow(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	const n = 1000
+	rnd := rand.New(newDefaultRandSource())
+	x, y := GaussianGen{Delta: -10}.Generate(rnd, n)
+	p := NewMom(0.5)
+
+	// The evidence is overwhelming, so the e-value overflows.
+	e := p.EValue(x, y)
+	if !math.IsInf(e, 1) {
+		t.Fatalf("e-value %g does not overflow", e)
+	}
+	if !(e > 1./alpha) {
+		t.Errorf("overflowed e-value does not reject")
+	}
+	l := p.LogEValue(x, y)
+	if math.IsInf(l, 0) || math.IsNaN(l) || !(l > math.Log(math.MaxFloat64)) {
+		t.Errorf("unexpected log e-value %f", l)
+	}
+
+	// A sequential test that first evaluates the e-value after it overflows still rejects.
+	st := NewSequentialTest(p, alpha, SequentialTestOptions{MinSamplesPerGroup: n})
+	for i := range n {
+		st.Push(1, x[i])
+		st.Push(2, y[i])
+	}
+	if st.StopT() != 2*n || !math.IsInf(st.EValue(), 1) {
+		t.Errorf("unexpected stopping time %d e-value %g", st.StopT(), st.EValue())
+	}
+
+	// LogEValue agrees with EValue where the e-value is representable.
+	x, y = x[:20], y[:20]
+	if l, want := p.LogEValue(x, y), math.Log(p.EValue(x, y)); l != want {
+		t.Errorf("got %f want %f", l, want)
+	}
+}
+
+func TestEValueBoundaryNoPanic(t *testing.T) {
+	t.Parallel()
+	// Infinite intermediate results, such as Inf/Inf in the high precision fallback, must not panic.
+	tests := []TStatistic{
+		{T: math.Inf(1), Nu: 1e300, NEff: 1e12, Sp: 1},
+		{T: math.Inf(-1), Nu: 1e300, NEff: 1e12, Sp: 1},
+		{T: math.Inf(1), Nu: 1e300, NEff: 0.5, Sp: 1},
+		{T: 3, Nu: 1e300, NEff: 1e12, Sp: 1},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			for _, p := range []*Mom{{G: 0.1339827}, {G: 0.1339827, Prec: 64}} {
+				if e := p.EValueFromTStat(test); e < 0 {
+					t.Errorf("negative e-value %f", e)
+				}
+			}
+		})
+	}
+
+	if v := recoverNaN(func() float64 { panic("hypergeo") }); !math.IsNaN(v) {
+		t.Errorf("got %f want NaN", v)
+	}
+	if v := recoverNaN(func() float64 { return 2 }); v != 2 {
+		t.Errorf("got %f want 2", v)
+	}
+	if v := hypergeo(1, 3./2, 1./2, 0.5); !scalar.EqualWithinRel(v, mathext.Hypergeo(1, 3./2, 1./2, 0.5), 1e-15) {
+		t.Errorf("got %f want %f", v, mathext.Hypergeo(1, 3./2, 1./2, 0.5))
+	}
+}
+
+func TestEValueComponents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		t    float64
+		nu   float64
+		nEff float64
+	}{
+		{t: 0, nu: 2, nEff: 1},
+		{t: 2.244057, nu: 15, nEff: 4.235294},
+		{t: 5.976485, nu: 116, nEff: 29.2373},
+	}
+	p := &Mom{G: 0.1339827}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			prefactor, hyper := p.EValueComponents(test.t, test.nu, test.nEff)
+			if want := math.Pow(1+test.nEff*p.G, -1.5); !scalar.EqualWithinRel(prefactor, want, 1e-15) {
+				t.Errorf("prefactor: got %f want %f", prefactor, want)
+			}
+			if e := p.eValue(test.t, test.nu, test.nEff); prefactor*hyper != e {
+				t.Errorf("prefactor*hyper: got %f want %f", prefactor*hyper, e)
+			}
+		})
+	}
+
+	// The hypergeometric function overflows, whereas the prefactor does not.
+	prefactor, hyper := p.EValueComponents(58.97, 1000, 250.5)
+	if !(prefactor > 0 && prefactor < 1) || !(math.IsNaN(hyper) || math.IsInf(hyper, 1)) {
+		t.Errorf("unexpected components of overflow: %g %g", prefactor, hyper)
+	}
+}
+
+func TestEValueNull(t *testing.T) {
+	t.Parallel()
+	p := &Mom{G: 0.1339827}
+	// The e-value at n1=n2=2 in TestEValueT.
+	if e := p.EValueNull(2, 1); !scalar.EqualWithinRel(e, 0.8281145, 2e-6) {
+		t.Errorf("EValueNull(2, 1): got %f want %f", e, 0.8281145)
+	}
+	for _, n := range [][2]float64{{2, 2}, {5, 3}, {30, 30}, {100, 40}, {1000, 1000}} {
+		nu, nEff := n[0]+n[1]-2, n[0]*n[1]/(n[0]+n[1])
+		if e, want := p.EValueNull(nu, nEff), p.eValue(0, nu, nEff); !scalar.EqualWithinRel(e, want, 1e-12) {
+			t.Errorf("EValueNull(%f, %f): got %f want %f", nu, nEff, e, want)
+		}
+	}
+}
+
+func TestNullQuantile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		n1    int
+		n2    int
+		alpha float64
+	}{
+		{n1: 5, n2: 5, alpha: 0.5},
+		{n1: 20, n2: 30, alpha: 0.2},
+		{n1: 100, n2: 100, alpha: 0.05},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			n1, n2 := float64(test.n1), float64(test.n2)
+			nu, nEff := n1+n2-2, n1*n2/(n1+n2)
+			p := &Mom{G: 0.1339827}
+			quantile := p.NullQuantile(nu, nEff, 1-test.alpha)
+			if quantile > 1/test.alpha {
+				t.Errorf("quantile %f exceeds Markov bound %f", quantile, 1/test.alpha)
+			}
+
+			// Simulate t-statistics under the null hypothesis.
+			rnd := rand.New(rand.NewPCG(uint64(i), 0))
+			dist := distuv.StudentsT{Sigma: 1, Nu: nu, Src: rnd}
+			const numSimulations = 10000
+			var exceeded float64
+			for range numSimulations {
+				if p.eValue(dist.Rand(), nu, nEff) > quantile {
+					exceeded++
+				}
+			}
+			if frac := exceeded / numSimulations; !scalar.EqualWithinAbs(frac, test.alpha, 0.015) {
+				t.Errorf("P(e > NullQuantile): got %f want %f", frac, test.alpha)
+			}
+		})
+	}
+}
+
+func TestCrossingProbability(t *testing.T) {
+	t.Parallel()
+	p := NewMom(0.5)
+	tests := []struct {
+		n1        int
+		n2        int
+		threshold float64
+	}{
+		{n1: 50, n2: 50, threshold: 20},
+		{n1: 200, n2: 200, threshold: 20},
+		{n1: 70, n2: 30, threshold: 20},
+		{n1: 100, n2: 100, threshold: 100},
+		{n1: 100, n2: 100, threshold: 2},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			nu, nEff := float64(test.n1+test.n2-2), EffectiveSampleSize(test.n1, test.n2)
+			prob := p.CrossingProbability(nu, nEff, test.threshold, 1000, rand.NewPCG(uint64(i), 1))
+			// Ville's inequality.
+			if alpha := 1 / test.threshold; !(0 < prob && prob <= alpha) {
+				t.Errorf("crossing probability %f exceeds %f", prob, alpha)
+			}
+		})
+	}
+
+	// Monitoring longer can only increase the crossing probability.
+	short := p.CrossingProbability(18, 5, 20, 1000, rand.NewPCG(0, 1))
+	long := p.CrossingProbability(398, 100, 20, 1000, rand.NewPCG(0, 1))
+	if !(short <= long) {
+		t.Errorf("crossing probability decreases with sample size: %f %f", short, long)
+	}
+
+	// A nil random source defaults to a fixed source.
+	if prob, want := p.CrossingProbability(98, 25, 20, 100, nil), p.CrossingProbability(98, 25, 20, 100, newDefaultRandSource()); prob != want {
+		t.Errorf("nil random source: got %f want %f", prob, want)
+	}
+
+	// Inconsistent nu and nEff, such as an nEff larger than (nu+2)/4, and invalid thresholds or numbers of simulations yield NaN.
+	for _, test := range [][4]float64{{98, 26, 20, 100}, {98, 0, 20, 100}, {-1, 0.2, 20, 100}, {math.Inf(1), 25, 20, 100}, {math.NaN(), 25, 20, 100}, {98, 25, 0, 100}, {98, 25, 20, 0}} {
+		if prob := p.CrossingProbability(test[0], test[1], test[2], int(test[3]), nil); !math.IsNaN(prob) {
+			t.Errorf("CrossingProbability(%v): got %f want NaN", test, prob)
+		}
+	}
+}
+
+func TestEValueApproxSmallT(t *testing.T) {
+	t.Parallel()
+	for _, g := range []float64{0.001, 0.1339827, 10} {
+		p := &Mom{G: g}
+		for _, n := range [][2]float64{{2, 2}, {2, 10}, {10, 10}, {1000, 1000}} {
+			nu, nEff := n[0]+n[1]-2, n[0]*n[1]/(n[0]+n[1])
+			r := nEff * g / (1 + nEff*g)
+			for x := 0.; r*x*x < 0.1 && x*x < 0.1*nu; x += 0.01 {
+				e, approx := p.eValue(x, nu, nEff), p.EValueApproxSmallT(x, nu, nEff)
+				if !scalar.EqualWithinRel(approx, e, 0.01) {
+					t.Errorf("EValueApproxSmallT(%f, %f, %f) with G %f: got %f want %f", x, nu, nEff, g, approx, e)
+				}
+			}
+		}
+	}
+}
+
 func TestCI(t *testing.T) {
 	t.Parallel()
 	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
@@ -455,8 +1030,7 @@ func TestCI(t *testing.T) {
 	}
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
-			data = data[:test.n]
-			x, y := splitGray(data)
+			x, y := splitGray(data[:test.n])
 			if !(len(x) > 1 && len(y) > 1) {
 				return
 			}
@@ -474,18 +1048,536 @@ func TestCI(t *testing.T) {
 	}
 }
 
-func TestGetNPlan(t *testing.T) {
+func TestCIInvalidAlpha(t *testing.T) {
 	t.Parallel()
-	tests := []struct {
-		alpha    float64
-		beta     float64
-		deltaMin float64
-		nPlan    NPlan
-	}{
-		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, nPlan: NPlan{N: 94, Mean: 56, Batch: 113}},
-		{alpha: 0.01, beta: 0.1, deltaMin: 0.7688172, nPlan: NPlan{N: 75, Mean: 41, Batch: 83}},
-	}
-	for i, test := range tests {
+	x, y := []float64{1, 2, 3, 4}, []float64{2, 4, 6, 9}
+	p := &Mom{G: 0.5}
+	for i, alpha := range []float64{0, 1, -0.1, 1.5, math.NaN()} {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if tAlpha := p.CriticalT(5, 2, alpha); !math.IsNaN(tAlpha) {
+				t.Errorf("CriticalT(%f) = %f, want NaN", alpha, tAlpha)
+			}
+			if ci := p.CI(x, y, alpha); !math.IsNaN(ci[0]) || !math.IsNaN(ci[1]) {
+				t.Errorf("CI(%f) = %v, want NaN", alpha, ci)
+			}
+			if ci := p.CIEffectSize(x, y, alpha); !math.IsNaN(ci[0]) || !math.IsNaN(ci[1]) {
+				t.Errorf("CIEffectSize(%f) = %v, want NaN", alpha, ci)
+			}
+		})
+	}
+}
+
+func TestCIMulti(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := NewMom(0.5176537)
+	alphas := []float64{0.05, 0.1, 0.01, 2, 0.001}
+	cis := p.CIMulti(x, y, alphas)
+	if len(cis) != len(alphas) {
+		t.Fatalf("got %d intervals want %d", len(cis), len(alphas))
+	}
+	for i, alpha := range alphas {
+		want := p.CI(x, y, alpha)
+		for j := range 2 {
+			if !(scalar.EqualWithinRel(cis[i][j], want[j], 1e-12) || math.IsNaN(cis[i][j]) && math.IsNaN(want[j])) {
+				t.Errorf("alpha %f: got %v want %v", alpha, cis[i], want)
+			}
+		}
+	}
+
+	// Smaller alphas give wider intervals.
+	for _, k := range [][2]int{{1, 0}, {0, 2}, {2, 4}} {
+		wide, narrow := cis[k[1]], cis[k[0]]
+		if !(wide[0] < narrow[0] && narrow[1] < wide[1]) {
+			t.Errorf("interval %v of alpha %f is not wider than %v of alpha %f", wide, alphas[k[1]], narrow, alphas[k[0]])
+		}
+	}
+}
+
+func TestCIWithCritical(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	p := &Mom{G: 0.1339827}
+	for _, n := range []int{4, 10, 30, len(data)} {
+		x, y := splitGray(data[:n])
+		ci, criticalT := p.CIWithCritical(x, y, 0.05)
+		if want := p.CI(x, y, 0.05); ci != want {
+			t.Errorf("CIWithCritical(data[:%d]) = %v, want %v", n, ci, want)
+		}
+		ts := TStat(x, y, 0)
+		if want := p.CriticalT(ts.Nu, ts.NEff, 0.05); criticalT != want {
+			t.Errorf("critical t of data[:%d]: got %f want %f", n, criticalT, want)
+		}
+		if math.IsInf(criticalT, 1) {
+			continue
+		}
+		if width, want := (ci[1]-ci[0])/2, ts.Sp/math.Sqrt(ts.NEff)*criticalT; !scalar.EqualWithinRel(width, want, 1e-12) {
+			t.Errorf("half width of data[:%d]: got %f want %f", n, width, want)
+		}
+	}
+}
+
+func TestCIPhi0(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	tests := []struct {
+		n int
+	}{
+		{n: 20},
+		{n: 30},
+		{n: 60},
+		{n: 121},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			x, y := splitGray(data[:test.n])
+			const alpha = 0.05
+			p := &Mom{G: 0.1339827}
+			ci := p.CI(x, y, alpha)
+			for phi0 := -3.0; phi0 <= 6; phi0 += 0.01 {
+				// Skip the boundaries, where the decision is decided by numerical noise.
+				if math.Abs(phi0-ci[0]) < 1e-6 || math.Abs(phi0-ci[1]) < 1e-6 {
+					continue
+				}
+				inside := ci[0] < phi0 && phi0 < ci[1]
+				e := p.EValuePhi0(x, y, phi0)
+				if inside != (e < 1./alpha) {
+					t.Errorf("inconsistent CI %v and EValuePhi0(data[:%d], %f) = %f", ci, test.n, phi0, e)
+				}
+			}
+		})
+	}
+}
+
+func TestCIExcludes(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	const alpha = 0.05
+	p := &Mom{G: 0.1339827}
+	var numExcluded int
+	for n := 4; n <= len(data); n++ {
+		x, y := splitGray(data[:n])
+		excludes := CIExcludes(p.CI(x, y, alpha), 0)
+		if rejects := p.EValue(x, y) > 1./alpha; excludes != rejects {
+			t.Errorf("inconsistent CIExcludes %t and EValue rejection %t at data[:%d]", excludes, rejects, n)
+		}
+		if excludes {
+			numExcluded++
+		}
+	}
+	if numExcluded == 0 {
+		t.Errorf("CI never excludes 0")
+	}
+
+	infinite := [2]float64{math.Inf(-1), math.Inf(1)}
+	if CIExcludes(infinite, 0) || CIExcludes(infinite, 1e300) {
+		t.Errorf("infinite CI excludes a value")
+	}
+	if !CIExcludes([2]float64{math.Inf(-1), 1}, 2) || CIExcludes([2]float64{math.Inf(-1), 1}, -1e300) {
+		t.Errorf("wrong exclusion of half infinite CI")
+	}
+}
+
+func TestCIEffectSize(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	const alpha = 0.05
+	p := &Mom{G: 0.1339827}
+	prevWidth := math.Inf(1)
+	for _, n := range []int{20, 30, 60, 121} {
+		x, y := splitGray(data[:n])
+		ci := p.CIEffectSize(x, y, alpha)
+
+		// The interval contains the point estimate of Cohen's d.
+		ts := TStat(x, y, 0)
+		d := (ts.Mean1 - ts.Mean2) / ts.Sp
+		if !(ci[0] < d && d < ci[1]) {
+			t.Errorf("CIEffectSize(data[:%d]) = %v does not contain %f", n, ci, d)
+		}
+
+		// The interval is CI in units of the pooled standard deviation.
+		mean := p.CI(x, y, alpha)
+		if !scalar.EqualWithinRel(ci[0]*ts.Sp, mean[0], 1e-9) || !scalar.EqualWithinRel(ci[1]*ts.Sp, mean[1], 1e-9) {
+			t.Errorf("inconsistent CIEffectSize(data[:%d]) = %v and CI = %v", n, ci, mean)
+		}
+
+		// The interval narrows with sample size.
+		width := ci[1] - ci[0]
+		if !(width < prevWidth) {
+			t.Errorf("CIEffectSize(data[:%d]) width %f is not narrower than %f", n, width, prevWidth)
+		}
+		prevWidth = width
+	}
+}
+
+func TestClassicalCriticalT(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		nu    float64
+		alpha float64
+		want  float64
+	}{
+		{nu: 1, alpha: 0.05, want: 12.7062},
+		{nu: 10, alpha: 0.05, want: 2.228139},
+		{nu: 30, alpha: 0.01, want: 2.749996},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			c := ClassicalCriticalT(test.nu, test.alpha)
+			if !scalar.EqualWithinRel(c, test.want, 1e-5) {
+				t.Errorf("unexpected result ClassicalCriticalT(%f, %f): got %f want %f", test.nu, test.alpha, c, test.want)
+			}
+		})
+	}
+}
+
+// TestRootFindingRegression checks that the critical t of CI and the batch sample sizes of GetNPlan, which share solveBrentMono, are those before they were unified.
+func TestRootFindingRegression(t *testing.T) {
+	t.Parallel()
+	p := NewMom(0.5176537)
+	criticalTs := []struct {
+		nu    float64
+		nEff  float64
+		alpha float64
+		want  float64
+	}{
+		{nu: 1, nEff: 0.5, alpha: 0.05, want: math.Inf(1)},
+		{nu: 2, nEff: 1, alpha: 0.05, want: math.Inf(1)},
+		{nu: 10, nEff: 3, alpha: 0.05, want: 26.78015928238893},
+		{nu: 48, nEff: 12.5, alpha: 0.01, want: 3.809031682891347},
+		{nu: 198, nEff: 50, alpha: 0.05, want: 3.01381168957221},
+		{nu: 1998, nEff: 500, alpha: 0.001, want: 4.554601248748457},
+		{nu: 5, nEff: 1.5, alpha: 0.001, want: math.Inf(1)},
+	}
+	for _, test := range criticalTs {
+		if c := p.CriticalT(test.nu, test.nEff, test.alpha); !(c == test.want || scalar.EqualWithinRel(c, test.want, 1e-14)) {
+			t.Errorf("CriticalT(%f, %f, %f): got %v want %v", test.nu, test.nEff, test.alpha, c, test.want)
+		}
+	}
+
+	batches := []struct {
+		alpha float64
+		beta  float64
+		delta float64
+		ratio float64
+		n1    int
+		n2    int
+	}{
+		{alpha: 0.05, beta: 0.2, delta: 0.51765, ratio: 1, n1: 113, n2: 113},
+		{alpha: 0.05, beta: 0.2, delta: 0.51765, ratio: 2, n1: 85, n2: 169},
+		{alpha: 0.01, beta: 0.1, delta: 0.3, ratio: 1, n1: 527, n2: 527},
+		{alpha: 0.05, beta: 0.2, delta: 0.1, ratio: 1, n1: 2971, n2: 2971},
+		{alpha: 0.1, beta: 0.5, delta: 2, ratio: 1, n1: 5, n2: 5},
+		{alpha: 0.05, beta: 0.2, delta: 1.5, ratio: 0.5, n1: 23, n2: 12},
+	}
+	for _, test := range batches {
+		if n1, n2 := getNPlanBatch(test.alpha, test.beta, test.delta, test.ratio, NewMom(test.delta)); n1 != test.n1 || n2 != test.n2 {
+			t.Errorf("getNPlanBatch(%f, %f, %f, %f): got %d %d want %d %d", test.alpha, test.beta, test.delta, test.ratio, n1, n2, test.n1, test.n2)
+		}
+	}
+
+	batchesN2 := []struct {
+		alpha float64
+		beta  float64
+		delta float64
+		n2    int
+		n1    int
+	}{
+		{alpha: 0.05, beta: 0.2, delta: 0.51765, n2: 113, n1: 113},
+		{alpha: 0.05, beta: 0.2, delta: 0.51765, n2: 200, n1: 79},
+		{alpha: 0.05, beta: 0.2, delta: 0.51765, n2: 60, n1: 768},
+		{alpha: 0.05, beta: 0.2, delta: 0.51765, n2: 50, n1: -1},
+		{alpha: 0.01, beta: 0.1, delta: 0.3, n2: 1000, n1: 357},
+		{alpha: 0.1, beta: 0.5, delta: 2, n2: 5, n1: 5},
+		{alpha: 0.1, beta: 0.5, delta: 2, n2: 1, n1: -1},
+	}
+	for _, test := range batchesN2 {
+		if n1 := getNPlanBatchN2(test.alpha, test.beta, test.delta, test.n2, NewMom(test.delta)); n1 != test.n1 {
+			t.Errorf("getNPlanBatchN2(%f, %f, %f, %d): got %d want %d", test.alpha, test.beta, test.delta, test.n2, n1, test.n1)
+		}
+	}
+}
+
+func TestRejectionRegion(t *testing.T) {
+	t.Parallel()
+	p := NewMom(0.5176537)
+	tests := []struct {
+		nu    float64
+		nEff  float64
+		alpha float64
+		// ratio is the ratio between the e-value and classical thresholds, which is the price of anytime validity.
+		ratio float64
+	}{
+		{nu: 10, nEff: 3, alpha: 0.05, ratio: 12.019},
+		{nu: 48, nEff: 12.5, alpha: 0.05, ratio: 1.509},
+		{nu: 198, nEff: 50, alpha: 0.05, ratio: 1.528},
+		{nu: 198, nEff: 50, alpha: 0.01, ratio: 1.350},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			lo, hi := p.RejectionRegion(test.nu, test.nEff, test.alpha)
+			if lo != -hi || hi != p.CriticalT(test.nu, test.nEff, test.alpha) {
+				t.Errorf("unexpected region (%f, %f)", lo, hi)
+			}
+			classical := ClassicalCriticalT(test.nu, test.alpha)
+			if !(hi > classical) {
+				t.Errorf("e-value threshold %f is not wider than the classical %f", hi, classical)
+			}
+			if r := hi / classical; !scalar.EqualWithinAbs(r, test.ratio, 1e-3) {
+				t.Errorf("got ratio %f want %f", r, test.ratio)
+			}
+			// The e-value is exactly 1/alpha at the thresholds.
+			if e := p.eValue(lo, test.nu, test.nEff); !scalar.EqualWithinRel(e, 1/test.alpha, 1e-9) {
+				t.Errorf("e-value at lo: got %f want %f", e, 1/test.alpha)
+			}
+		})
+	}
+}
+
+func TestEffectiveSampleSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		n1   int
+		n2   int
+		nEff float64
+	}{
+		{n1: 1, n2: 1, nEff: 0.5},
+		{n1: 10, n2: 40, nEff: 8},
+		{n1: 40, n2: 10, nEff: 8},
+		{n1: 3, n2: 6, nEff: 2},
+		{n1: 5, n2: 1000000, nEff: 5. * 1000000 / 1000005},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if nEff := EffectiveSampleSize(test.n1, test.n2); !scalar.EqualWithinRel(nEff, test.nEff, 1e-15) {
+				t.Errorf("got %f want %f", nEff, test.nEff)
+			}
+		})
+	}
+
+	// Equal group sizes halve the sample size.
+	for _, n := range []int{2, 7, 100, 12345} {
+		if nEff := EffectiveSampleSize(n, n); nEff != float64(n)/2 {
+			t.Errorf("n %d: got %f want %f", n, nEff, float64(n)/2)
+		}
+	}
+}
+
+func TestPooledVariance(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	carletonX, carletonY := splitGray(data)
+	tests := []struct {
+		x []float64
+		y []float64
+		v float64
+	}{
+		{x: []float64{1, 2, 3}, y: []float64{4, 6}, v: 4. / 3},
+		{x: []float64{5}, y: []float64{1, 3, 5}, v: 4},
+		{x: []float64{2, 2}, y: []float64{7, 7, 7}, v: 0},
+		{x: carletonX, y: carletonY, v: math.Pow(TStat(carletonX, carletonY, 0).Sp, 2)},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			v := PooledVariance(test.x, test.y)
+			if !scalar.EqualWithinRel(v, test.v, 1e-12) {
+				t.Errorf("got %f want %f", v, test.v)
+			}
+			if sp := TStat(test.x, test.y, 0).Sp; math.Sqrt(v) != sp {
+				t.Errorf("square root %f differs from Sp %f", math.Sqrt(v), sp)
+			}
+		})
+	}
+}
+
+func TestPValueStudent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		nu    float64
+		alpha float64
+	}{
+		{nu: 1, alpha: 0.05},
+		{nu: 10, alpha: 0.05},
+		{nu: 100, alpha: 0.01},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			// The p-value at the classical critical t is alpha, on either side.
+			tAlpha := ClassicalCriticalT(test.nu, test.alpha)
+			for _, ts := range []float64{tAlpha, -tAlpha} {
+				if p := PValueStudent(TStatistic{Nu: test.nu, T: ts}); !scalar.EqualWithinRel(p, test.alpha, 1e-9) {
+					t.Errorf("PValueStudent(%f) = %f, want %f", ts, p, test.alpha)
+				}
+			}
+		})
+	}
+
+	// PValueStudent is deterministic, and so is bit-identical across runs.
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	ts := TStat(x, y, 0)
+	want := PValueStudent(ts)
+	for range 100 {
+		if p := PValueStudent(ts); math.Float64bits(p) != math.Float64bits(want) {
+			t.Fatalf("PValueStudent not deterministic: got %v want %v", p, want)
+		}
+	}
+}
+
+func TestExpectedEValue(t *testing.T) {
+	t.Parallel()
+	p := NewMom(0.5)
+	// Under the null hypothesis, the expected e-value is 1.
+	for _, n := range []int{2, 10, 100} {
+		if e := ExpectedEValue(n, n, 0, p); !scalar.EqualWithinRel(e, 1, 1e-9) {
+			t.Errorf("ExpectedEValue(%d, %d, 0): got %f want 1", n, n, e)
+		}
+	}
+
+	// The expected e-value increases with the sample size and the effect size.
+	deltas := []float64{0.3, 0.5, 1}
+	ns := []int{5, 10, 30, 100}
+	for _, delta := range deltas {
+		for i := 1; i < len(ns); i++ {
+			if e0, e1 := ExpectedEValue(ns[i-1], ns[i-1], delta, p), ExpectedEValue(ns[i], ns[i], delta, p); !(e0 < e1) {
+				t.Errorf("non-increasing in n at delta %f: %d %f, %d %f", delta, ns[i-1], e0, ns[i], e1)
+			}
+		}
+	}
+	for _, n := range ns {
+		for i := 1; i < len(deltas); i++ {
+			if e0, e1 := ExpectedEValue(n, n, deltas[i-1], p), ExpectedEValue(n, n, deltas[i], p); !(e0 < e1) {
+				t.Errorf("non-increasing in delta at n %d: %f %f, %f %f", n, deltas[i-1], e0, deltas[i], e1)
+			}
+		}
+	}
+
+	// At the batch sample size, the e-value exceeds 1/alpha with probability 1-beta, which bounds the expectation from below.
+	const alpha, beta, deltaMin = 0.05, 0.2, 0.51765
+	n1, n2 := getNPlanBatch(alpha, beta, deltaMin, 1, NewMom(deltaMin))
+	if e := ExpectedEValue(n1, n2, deltaMin, NewMom(deltaMin)); !(e >= (1-beta)/alpha) {
+		t.Errorf("ExpectedEValue(%d, %d, %f): got %f want at least %f", n1, n2, deltaMin, e, (1-beta)/alpha)
+	}
+}
+
+func TestForecastRemaining(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	p := NewMom(0.5176537)
+
+	// At n=20 the forecasted stop is close to the actual stop at 30.
+	const n = 20
+	x, y := splitGray(data[:n])
+	remaining := p.ForecastRemaining(x, y, 0.05)
+	if remaining != 11 {
+		t.Errorf("got %d want %d", remaining, 11)
+	}
+	if stop := n + remaining; math.Abs(float64(stop-30)) > 5 {
+		t.Errorf("forecasted stop %d is far from the actual stop at 30", stop)
+	}
+
+	// The expected e-value already exceeds 1/alpha.
+	x, y = splitGray(data[:25])
+	if r := p.ForecastRemaining(x, y, 0.05); r != 0 {
+		t.Errorf("got %d want 0", r)
+	}
+
+	// No forecast without an observed effect.
+	for _, test := range [][2][]float64{{{1, 2}, {1, 2}}, {{1}, {2}}, {{1, 1}, {2, 2}}} {
+		if r := p.ForecastRemaining(test[0], test[1], 0.05); r != -1 {
+			t.Errorf("ForecastRemaining(%v, %v): got %d want -1", test[0], test[1], r)
+		}
+	}
+	if r := p.ForecastRemaining(x, y, 0); r != -1 {
+		t.Errorf("invalid alpha: got %d want -1", r)
+	}
+
+	// A tiny effect needs more than maxForecast observations.
+	if r := p.ForecastRemaining([]float64{-0.996, 1.004}, []float64{-1, 1}, 0.05); r != -1 {
+		t.Errorf("tiny effect: got %d want -1", r)
+	}
+}
+
+func TestCurrentMDE(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	const alpha = 0.05
+	p := &Mom{G: 0.1339827}
+	// The e-value of tiny groups is bounded below 1/alpha, so no effect is detectable.
+	if x, y := splitGray(data[:10]); !math.IsInf(p.CurrentMDE(x, y, alpha), 1) {
+		t.Errorf("unexpected finite CurrentMDE(data[:10]) %f", p.CurrentMDE(x, y, alpha))
+	}
+
+	prev := math.Inf(1)
+	for _, n := range []int{20, 40, 80, 121} {
+		x, y := splitGray(data[:n])
+		mde := p.CurrentMDE(x, y, alpha)
+		if !(mde < prev) {
+			t.Errorf("CurrentMDE(data[:%d]) = %f does not shrink from %f", n, mde, prev)
+		}
+		prev = mde
+		if e := ExpectedEValue(len(x), len(y), mde, p); !scalar.EqualWithinRel(e, 1./alpha, 1e-3) {
+			t.Errorf("ExpectedEValue at CurrentMDE(data[:%d]) = %f: got %f want %f", n, mde, e, 1./alpha)
+		}
+	}
+}
+
+func TestSamplesForTargetE(t *testing.T) {
+	t.Parallel()
+	p := NewMom(0.5)
+	tests := []struct {
+		targetE float64
+		delta   float64
+		ratio   float64
+		n1      int
+		n2      int
+	}{
+		{targetE: 20, delta: 0.5, ratio: 1, n1: 25, n2: 25},
+		{targetE: 20, delta: 1, ratio: 1, n1: 14, n2: 14},
+		{targetE: 100, delta: 0.5, ratio: 2, n1: 25, n2: 50},
+		// The expected e-value is always 1 under the null hypothesis.
+		{targetE: 20, delta: 0, ratio: 1, n1: -1, n2: -1},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			n1, n2 := SamplesForTargetE(test.targetE, test.delta, p, test.ratio)
+			if n1 != test.n1 || n2 != test.n2 {
+				t.Fatalf("SamplesForTargetE(%f, %f, %f): got %d %d want %d %d", test.targetE, test.delta, test.ratio, n1, n2, test.n1, test.n2)
+			}
+			if n1 == -1 {
+				return
+			}
+			// The sizes are the smallest that reach the target.
+			if e := ExpectedEValue(n1, n2, test.delta, p); !(e >= test.targetE && e < 1.2*test.targetE) {
+				t.Errorf("ExpectedEValue(%d, %d): got %f want about %f", n1, n2, e, test.targetE)
+			}
+			if e := ExpectedEValue(n1-1, int(math.Ceil(test.ratio*float64(n1-1))), test.delta, p); !(e < test.targetE) {
+				t.Errorf("ExpectedEValue(%d): got %f want less than %f", n1-1, e, test.targetE)
+			}
+		})
+	}
+}
+
+func TestGetNPlan(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alpha    float64
+		beta     float64
+		deltaMin float64
+		nPlan    NPlan
+	}{
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, nPlan: NPlan{N: 94, Mean: 56, Batch: 113}},
+		{alpha: 0.01, beta: 0.1, deltaMin: 0.7688172, nPlan: NPlan{N: 75, Mean: 41, Batch: 83}},
+	}
+	for i, test := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
 			nPlan := GetNPlan(test.alpha, test.beta, test.deltaMin)
 			if nPlan.N != test.nPlan.N {
@@ -501,6 +1593,561 @@ func TestGetNPlan(t *testing.T) {
 	}
 }
 
+func TestGetNPlanMom(t *testing.T) {
+	t.Parallel()
+	const alpha, beta = 0.05, 0.2
+	opt := GetNPlanOptions{NumSimulations: 200}
+	nPlan := func(deltaMin float64, p *Mom) NPlan {
+		o := opt
+		o.Mom = p
+		return GetNPlan(alpha, beta, deltaMin, o)
+	}
+
+	// The default e-process is tuned to the planning effect size.
+	if got, want := nPlan(0.5, NewMom(0.5)), nPlan(0.5, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected NPlan of NewMom(deltaMin): got %d want %d", got.N, want.N)
+	}
+
+	// At a fixed planning effect size, a mistuned prior scale needs more samples.
+	tuned := nPlan(0.5, NewMom(0.5)).N
+	for _, scale := range []float64{0.05, 3} {
+		if n := nPlan(0.5, NewMom(scale)).N; !(n > tuned) {
+			t.Errorf("prior scale %f: N %d not larger than tuned %d", scale, n, tuned)
+		}
+	}
+
+	// At a fixed prior scale, a larger planning effect size needs fewer samples.
+	p := NewMom(0.5)
+	if small, large := nPlan(0.4, p).N, nPlan(0.8, p).N; !(large < small) {
+		t.Errorf("N of planning effect size 0.8, %d, not smaller than that of 0.4, %d", large, small)
+	}
+	if p.G != 0.125 {
+		t.Errorf("GetNPlan modified the e-process: G %f", p.G)
+	}
+}
+
+func TestContinueNPlan(t *testing.T) {
+	t.Parallel()
+	const alpha, beta, deltaMin = 0.05, 0.2, 0.51765
+	rsrc := newDefaultRandSource()
+	prev := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{NumSimulations: 300, Rsrc: rsrc})
+	nPlan := ContinueNPlan(prev, 200, alpha, beta, deltaMin, GetNPlanOptions{Rsrc: rsrc})
+	want := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{NumSimulations: 500})
+	if !slices.Equal(nPlan.StopT, want.StopT) {
+		t.Errorf("stopping times differ from a single run")
+	}
+	if nPlan.N != want.N || nPlan.Mean != want.Mean || nPlan.Batch != want.Batch || nPlan.Power() != want.Power() {
+		t.Errorf("got %d %d %d %f want %d %d %d %f", nPlan.N, nPlan.Mean, nPlan.Batch, nPlan.Power(), want.N, want.Mean, want.Batch, want.Power())
+	}
+	if len(prev.StopT) != 300 {
+		t.Errorf("prev is modified")
+	}
+
+	// Modifying the result leaves prev unchanged.
+	for _, extra := range []int{0, 10} {
+		e0, stopT0 := prev.EValue[0][0], prev.StopT[0]
+		nPlan := ContinueNPlan(prev, extra, alpha, beta, deltaMin)
+		nPlan.EValue[0][0]++
+		nPlan.StopT[0]++
+		if prev.EValue[0][0] != e0 || prev.StopT[0] != stopT0 {
+			t.Errorf("extra %d: modifying the result modifies prev", extra)
+		}
+	}
+}
+
+func TestGetNPlanAntithetic(t *testing.T) {
+	t.Parallel()
+	// stdErr returns the standard deviation of Mean over repeated planning with different random sources.
+	stdErr := func(antithetic bool) float64 {
+		var means []float64
+		for seed := range 40 {
+			opt := GetNPlanOptions{NumSimulations: 200, Rsrc: rand.NewPCG(uint64(seed), 7), Antithetic: antithetic}
+			means = append(means, float64(GetNPlan(0.05, 0.2, 0.51765, opt).Mean))
+		}
+		return stat.StdDev(means, nil)
+	}
+	plain, antithetic := stdErr(false), stdErr(true)
+	if !scalar.EqualWithinAbs(plain, 2.939, 1e-3) || !scalar.EqualWithinAbs(antithetic, 1.441, 1e-3) {
+		t.Errorf("got %f %f want %f %f", plain, antithetic, 2.939, 1.441)
+	}
+	if !(antithetic < plain) {
+		t.Errorf("antithetic standard error %f is not less than plain %f", antithetic, plain)
+	}
+
+	// A custom SymmetricGen supports antithetic sampling as well.
+	custom := GetNPlan(0.05, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 200, Antithetic: true, DataGen: customSymmetricGen{delta: 0.51765}})
+	builtin := GetNPlan(0.05, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 200, Antithetic: true})
+	if !slices.Equal(custom.StopT, builtin.StopT) {
+		t.Errorf("custom SymmetricGen differs from GaussianGen")
+	}
+}
+
+// A customSymmetricGen is a SymmetricGen outside of the package, which generates the same data as GaussianGen.
+type customSymmetricGen struct {
+	delta float64
+}
+
+func (gen customSymmetricGen) Generate(rnd *rand.Rand, n int) ([]float64, []float64) {
+	return GaussianGen{Delta: gen.delta}.Generate(rnd, n)
+}
+
+func (gen customSymmetricGen) Locations() (float64, float64) {
+	return gen.delta / 2, -gen.delta / 2
+}
+
+func TestGetNPlanConvergenceTol(t *testing.T) {
+	t.Parallel()
+	const numSimulations = 5000
+	full := GetNPlan(0.05, 0.2, 0.5, GetNPlanOptions{NumSimulations: numSimulations})
+	const tol = 0.02
+	adaptive := GetNPlan(0.05, 0.2, 0.5, GetNPlanOptions{NumSimulations: numSimulations, ConvergenceTol: tol})
+	if !(len(adaptive.StopT) < numSimulations) {
+		t.Errorf("adaptive run used all %d simulations", len(adaptive.StopT))
+	}
+	if len(adaptive.StopT)%convergenceCheckInterval != 0 {
+		t.Errorf("adaptive run stopped between checks at %d", len(adaptive.StopT))
+	}
+	// The adaptive run shares its random source with the full run, and is thus a prefix of it.
+	if !slices.Equal(adaptive.StopT, full.StopT[:len(adaptive.StopT)]) {
+		t.Errorf("adaptive run is not a prefix of the full run")
+	}
+	if math.Abs(float64(adaptive.N-full.N)) > 2*tol*float64(full.N) {
+		t.Errorf("adaptive N %d too far from full N %d", adaptive.N, full.N)
+	}
+}
+
+func TestGetNPlanN2(t *testing.T) {
+	t.Parallel()
+	const alpha, beta, deltaMin = 0.05, 0.2, 1
+	balanced := GetNPlan(alpha, beta, deltaMin)
+	tests := []struct {
+		n2    int
+		nPlan NPlan
+	}{
+		{n2: 30, nPlan: NPlan{N: 23, Mean: 13, Batch: 34}},
+		{n2: 20, nPlan: NPlan{N: 41, Mean: 19, Batch: 69}},
+		// The desired power is out of reach, since nEff is bounded by n2.
+		{n2: 10, nPlan: NPlan{N: -1, Mean: -1, Batch: -1}},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			nPlan := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{N2: test.n2})
+			if nPlan.N != test.nPlan.N || nPlan.Mean != test.nPlan.Mean || nPlan.Batch != test.nPlan.Batch {
+				t.Errorf("GetNPlan(N2: %d): got %d %d %d want %d %d %d", test.n2, nPlan.N, nPlan.Mean, nPlan.Batch, test.nPlan.N, test.nPlan.Mean, test.nPlan.Batch)
+			}
+			// The first group grows to compensate for a second group smaller than that of the balanced design.
+			if nPlan.N != notStopped && test.n2 < balanced.N && !(nPlan.N > balanced.N) {
+				t.Errorf("GetNPlan(N2: %d).N = %d is not larger than the balanced %d", test.n2, nPlan.N, balanced.N)
+			}
+		})
+	}
+}
+
+func TestSampleSchedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ratio float64
+		n1    []int
+		n2    []int
+	}{
+		{ratio: 1, n1: []int{1, 2, 3, 4, 5}, n2: []int{1, 2, 3, 4, 5}},
+		{ratio: 2, n1: []int{1, 2, 3, 4, 5}, n2: []int{2, 4, 6, 8, 10}},
+		{ratio: 0.5, n1: []int{1, 2, 3, 4, 5}, n2: []int{1, 1, 2, 2, 3}},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			n1, n2 := SampleSchedule(len(test.n1), test.ratio)
+			if !slices.Equal(n1, test.n1) || !slices.Equal(n2, test.n2) {
+				t.Errorf("got %v %v want %v %v", n1, n2, test.n1, test.n2)
+			}
+		})
+	}
+
+	// The schedule is that of the simulations of GetNPlan.
+	const alpha, beta, deltaMin = 0.05, 0.2, 0.51765
+	for _, ratio := range []float64{1, 2} {
+		opt := GetNPlanOptions{Ratio: ratio, NumSimulations: 1, Rsrc: rand.NewPCG(1, 2)}
+		nPlan := GetNPlan(alpha, beta, deltaMin, opt)
+		o, err := newGetNPlanOptions(alpha, beta, deltaMin, []GetNPlanOptions{opt})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		batch1, batch2 := batchSizes(alpha, beta, deltaMin, o)
+		n1, n2 := SampleSchedule(batch1, ratio)
+		x, y := GaussianGen{Delta: deltaMin}.Generate(rand.New(rand.NewPCG(1, 2)), max(batch1, batch2, n2[len(n2)-1]))
+		for i, e := range nPlan.EValue[0] {
+			if want := o.Mom.EValue(x[:n1[i]], y[:n2[i]]); !scalar.EqualWithinRel(e, want, 1e-9) {
+				t.Errorf("ratio %f step %d: got %f want %f", ratio, i, e, want)
+			}
+		}
+	}
+}
+
+func TestGetNPlanErr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alpha    float64
+		beta     float64
+		deltaMin float64
+		options  GetNPlanOptions
+		err      error
+	}{
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{}},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{Ratio: 2, NumSimulations: 10}},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{Ratio: -1}, err: ErrInvalidOptions},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{Ratio: math.NaN()}, err: ErrInvalidOptions},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{Ratio: math.Inf(1)}, err: ErrInvalidOptions},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{NumSimulations: -1}, err: ErrInvalidOptions},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{N2: -1}, err: ErrInvalidOptions},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{ConvergenceTol: -0.1}, err: ErrInvalidOptions},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{ConvergenceTol: math.NaN()}, err: ErrInvalidOptions},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{NumSimulations: 10, Antithetic: true, DataGen: StudentTGen{Delta: 0.8, Nu: 30}}},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.51765, options: GetNPlanOptions{Antithetic: true, DataGen: MixtureGen{}}, err: ErrInvalidOptions},
+		// The desired power cannot be reached without an effect.
+		{alpha: 0.05, beta: 0.2, deltaMin: 0},
+		{alpha: 0.05, beta: 0.2, deltaMin: 0, options: GetNPlanOptions{N2: 100}},
+		{alpha: 0, beta: 0.2, deltaMin: 0.51765, err: ErrInvalidProbability},
+		{alpha: 1, beta: 0.2, deltaMin: 0.51765, err: ErrInvalidProbability},
+		{alpha: -0.1, beta: 0.2, deltaMin: 0.51765, err: ErrInvalidProbability},
+		{alpha: math.NaN(), beta: 0.2, deltaMin: 0.51765, err: ErrInvalidProbability},
+		{alpha: 0.05, beta: 0, deltaMin: 0.51765, err: ErrInvalidProbability},
+		{alpha: 0.05, beta: 1.5, deltaMin: 0.51765, err: ErrInvalidProbability},
+		{alpha: 0.05, beta: math.NaN(), deltaMin: 0.51765, err: ErrInvalidProbability},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			nPlan, err := GetNPlanErr(test.alpha, test.beta, test.deltaMin, test.options)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("unexpected error: got %v want %v", err, test.err)
+			}
+			if err != nil && !reflect.DeepEqual(nPlan, NPlan{}) {
+				t.Errorf("unexpected NPlan on error: %+v", nPlan)
+			}
+			if err == nil && test.deltaMin == 0 && (nPlan.N != notStopped || nPlan.Mean != notStopped || nPlan.Batch != notStopped) {
+				t.Errorf("unexpected infeasible NPlan %+v", nPlan)
+			}
+			if err == nil && test.deltaMin != 0 && nPlan.N <= 0 {
+				t.Errorf("unexpected NPlan %d", nPlan.N)
+			}
+			if got := GetNPlan(test.alpha, test.beta, test.deltaMin, test.options); !reflect.DeepEqual(got, nPlan) {
+				t.Errorf("inconsistent GetNPlan")
+			}
+		})
+	}
+}
+
+func TestNPlanLogEValueTrajectories(t *testing.T) {
+	t.Parallel()
+	nPlan := GetNPlan(0.05, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 100})
+	// Add saturated e-values.
+	nPlan.EValue = append(nPlan.EValue, []float64{1, math.Inf(1), 0})
+	trajectories := nPlan.LogEValueTrajectories()
+	if len(trajectories) != len(nPlan.EValue) {
+		t.Fatalf("unexpected number of trajectories: got %d want %d", len(trajectories), len(nPlan.EValue))
+	}
+	for i, eValues := range nPlan.EValue {
+		for j, e := range eValues {
+			l := trajectories[i][j]
+			if math.IsInf(l, 0) || math.IsNaN(l) {
+				t.Errorf("non-finite log e-value %f at %d %d", l, i, j)
+			}
+			if want := math.Log10(e); !math.IsInf(want, 0) && l != want {
+				t.Errorf("unexpected log e-value at %d %d: got %f want %f", i, j, l, want)
+			}
+		}
+	}
+	if last := trajectories[len(trajectories)-1]; !(last[0] == 0 && last[1] > 300 && last[2] < -300) {
+		t.Errorf("unexpected saturated log e-values %v", last)
+	}
+}
+
+func TestRecommendNumSamples(t *testing.T) {
+	t.Parallel()
+	const target = 2
+	const alpha, beta, deltaMin = 0.05, 0.2, 0.51765
+	numSamples := RecommendNumSamples(target, alpha, beta, deltaMin)
+	if numSamples != 1575 {
+		t.Errorf("unexpected number of samples: got %d want %d", numSamples, 1575)
+	}
+
+	// Estimate the standard errors by repeating GetNPlan with different random sources.
+	var ns, means []float64
+	for seed := range 20 {
+		nPlan := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{NumSimulations: numSamples, Rsrc: rand.NewPCG(uint64(seed), 1)})
+		ns, means = append(ns, float64(nPlan.N)), append(means, float64(nPlan.Mean))
+	}
+	stdErr := max(stat.StdDev(ns, nil), stat.StdDev(means, nil))
+	if !(target/2 < stdErr && stdErr < 2*target) {
+		t.Errorf("unexpected standard error: got %f want about %f", stdErr, float64(target))
+	}
+}
+
+func TestRecommendNumSamplesInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		target   float64
+		alpha    float64
+		beta     float64
+		deltaMin float64
+	}{
+		{target: 0, alpha: 0.05, beta: 0.2, deltaMin: 0.51765},
+		{target: math.NaN(), alpha: 0.05, beta: 0.2, deltaMin: 0.51765},
+		{target: 2, alpha: 0, beta: 0.2, deltaMin: 0.51765},
+		{target: 2, alpha: math.NaN(), beta: 0.2, deltaMin: 0.51765},
+		{target: 2, alpha: 0.05, beta: 0, deltaMin: 0.51765},
+		{target: 2, alpha: 0.05, beta: 1, deltaMin: 0.51765},
+		// The desired power cannot be reached without an effect.
+		{target: 2, alpha: 0.05, beta: 0.2, deltaMin: 0},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if n := RecommendNumSamples(test.target, test.alpha, test.beta, test.deltaMin); n != 0 {
+				t.Errorf("got %d want 0", n)
+			}
+		})
+	}
+}
+
+func TestNPlanValidate(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	nPlan := GetNPlan(alpha, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 100})
+	if err := nPlan.Validate(alpha); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := nPlan.Validate(0); !errors.Is(err, ErrInvalidProbability) {
+		t.Errorf("unexpected error: got %v want %v", err, ErrInvalidProbability)
+	}
+
+	i := slices.IndexFunc(nPlan.StopT, func(t int) bool { return t != notStopped })
+	corrupted := nPlan
+	corrupted.StopT = slices.Clone(nPlan.StopT)
+	corrupted.StopT[i]++
+	if err := corrupted.Validate(alpha); err == nil {
+		t.Errorf("corrupted stopping time is not reported")
+	}
+	corrupted.StopT = nPlan.StopT[1:]
+	if err := corrupted.Validate(alpha); err == nil {
+		t.Errorf("missing stopping time is not reported")
+	}
+	// The stopping times are those of a different significance level.
+	if err := nPlan.Validate(0.01); err == nil {
+		t.Errorf("different alpha is not reported")
+	}
+}
+
+func TestFirstCrossing(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		eValues []float64
+		stopT   int
+	}{
+		{eValues: nil, stopT: notStopped},
+		{eValues: []float64{1, 10, 19.9}, stopT: notStopped},
+		{eValues: []float64{1, 20, 21}, stopT: 3},
+		{eValues: []float64{25}, stopT: 1},
+		{eValues: []float64{1, math.Inf(1)}, stopT: 2},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if stopT := FirstCrossing(test.eValues, 0.05); stopT != test.stopT {
+				t.Errorf("got %d want %d", stopT, test.stopT)
+			}
+		})
+	}
+}
+
+func TestNPlanStopPercentiles(t *testing.T) {
+	t.Parallel()
+	nPlan := GetNPlan(0.05, 0.2, 0.51765)
+	ps := []float64{0.25, 0.5, 0.75, 0.8}
+	percentiles := nPlan.StopPercentiles(ps)
+	for i := 1; i < len(percentiles); i++ {
+		if !(percentiles[i-1] <= percentiles[i]) {
+			t.Errorf("non-monotone percentiles %v", percentiles)
+		}
+	}
+	if !(percentiles[0] <= nPlan.Mean && nPlan.Mean <= percentiles[len(percentiles)-1]) {
+		t.Errorf("percentiles %v do not bracket mean %d", percentiles, nPlan.Mean)
+	}
+	// The percentile at 1-beta is the planned sample size.
+	if percentiles[3] != nPlan.N {
+		t.Errorf("80th percentile: got %d want %d", percentiles[3], nPlan.N)
+	}
+	// Simulations that never stopped are reported as not stopped.
+	if p := nPlan.StopPercentiles([]float64{1}); p[0] != notStopped {
+		t.Errorf("100th percentile: got %d want %d", p[0], notStopped)
+	}
+}
+
+func TestNPlanStopInterval(t *testing.T) {
+	t.Parallel()
+	nPlan := GetNPlan(0.05, 0.2, 0.51765)
+	interval := nPlan.StopInterval(0.95)
+	if !(interval[0] <= nPlan.Mean && nPlan.Mean <= interval[1]) {
+		t.Errorf("interval %v does not contain mean %d", interval, nPlan.Mean)
+	}
+	if narrower := nPlan.StopInterval(0.5); !(interval[0] <= narrower[0] && narrower[1] <= interval[1]) {
+		t.Errorf("50%% interval %v is not inside 95%% interval %v", narrower, interval)
+	}
+	if interval := (NPlan{StopT: []int{notStopped}}).StopInterval(0.95); interval != [2]int{notStopped, notStopped} {
+		t.Errorf("unexpected interval without stopping: %v", interval)
+	}
+}
+
+func TestNPlanWriteCSV(t *testing.T) {
+	t.Parallel()
+	nPlan := GetNPlan(0.05, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 10})
+	buf := bytes.NewBuffer(nil)
+	if err := nPlan.WriteCSV(buf); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if header := []string{"sample", "step", "evalue", "stopT"}; !slices.Equal(rows[0], header) {
+		t.Errorf("unexpected header: got %v want %v", rows[0], header)
+	}
+	var numRows int
+	for _, eValues := range nPlan.EValue {
+		numRows += len(eValues)
+	}
+	if len(rows)-1 != numRows {
+		t.Errorf("unexpected number of rows: got %d want %d", len(rows)-1, numRows)
+	}
+	// A stopped simulation has as many steps as its stopping time.
+	steps := make(map[string]int)
+	for _, row := range rows[1:] {
+		steps[row[0]]++
+	}
+	for i, stopT := range nPlan.StopT {
+		if n := steps[strconv.Itoa(i)]; stopT != notStopped && n != stopT {
+			t.Errorf("unexpected number of steps of sample %d: got %d want %d", i, n, stopT)
+		}
+	}
+}
+
+func TestNoAliasing(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	xOrig, yOrig := slices.Clone(x), slices.Clone(y)
+	p := &Mom{G: 0.1339827}
+
+	e := p.EValue(x, y)
+	ci := p.CI(x, y, 0.05)
+	eWinsor := p.EValueWinsor(x, y, 0.1)
+	eAuto, _ := AutoEValue(x, y, 0.5176537)
+	typeI := CalibrationCheck(x, y, p, 0.05, 100)
+	// The inputs are not modified.
+	if !slices.Equal(x, xOrig) || !slices.Equal(y, yOrig) {
+		t.Fatalf("inputs are modified")
+	}
+
+	// Mutating the inputs does not affect the results.
+	for i := range x {
+		x[i] = 0
+	}
+	if e != p.EValue(xOrig, yOrig) || ci != p.CI(xOrig, yOrig, 0.05) || eWinsor != p.EValueWinsor(xOrig, yOrig, 0.1) {
+		t.Errorf("results are affected by mutated inputs")
+	}
+	if eAuto2, _ := AutoEValue(xOrig, yOrig, 0.5176537); eAuto != eAuto2 {
+		t.Errorf("AutoEValue is affected by mutated inputs: got %f want %f", eAuto, eAuto2)
+	}
+	if typeI2 := CalibrationCheck(xOrig, yOrig, p, 0.05, 100); typeI != typeI2 {
+		t.Errorf("CalibrationCheck is affected by mutated inputs: got %f want %f", typeI, typeI2)
+	}
+
+	// Computing percentiles does not reorder the stored stopping times.
+	nPlan := GetNPlan(0.05, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 100})
+	stopT := slices.Clone(nPlan.StopT)
+	nPlan.StopPercentiles([]float64{0.5})
+	if !slices.Equal(nPlan.StopT, stopT) {
+		t.Errorf("StopPercentiles modified StopT")
+	}
+}
+
+// benchmarkData returns the two sample data of the benchmarks.
+func benchmarkData() []struct {
+	name string
+	x    []float64
+	y    []float64
+} {
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	carletonX, carletonY := splitGray(data)
+	largeX, largeY := GaussianGen{Delta: 0.1}.Generate(rand.New(newDefaultRandSource()), 5000)
+	return []struct {
+		name string
+		x    []float64
+		y    []float64
+	}{
+		{name: "n=121", x: carletonX, y: carletonY},
+		{name: "n=10000", x: largeX, y: largeY},
+	}
+}
+
+func BenchmarkEValue(b *testing.B) {
+	p := &Mom{G: 0.1339827}
+	for _, bm := range benchmarkData() {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				p.EValue(bm.x, bm.y)
+			}
+		})
+	}
+}
+
+func BenchmarkEValueInto(b *testing.B) {
+	p := &Mom{G: 0.1339827}
+	for _, bm := range benchmarkData() {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var scratch TStatistic
+			for b.Loop() {
+				p.EValueInto(bm.x, bm.y, &scratch)
+			}
+		})
+	}
+}
+
+func BenchmarkCI(b *testing.B) {
+	p := &Mom{G: 0.1339827}
+	for _, bm := range benchmarkData() {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				p.CI(bm.x, bm.y, 0.05)
+			}
+		})
+	}
+}
+
+func BenchmarkGetNPlan(b *testing.B) {
+	tests := []struct {
+		name     string
+		deltaMin float64
+	}{
+		{name: "deltaMin=0.5", deltaMin: 0.51765},
+		{name: "deltaMin=0.1", deltaMin: 0.1},
+	}
+	for _, bm := range tests {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				GetNPlan(0.05, 0.2, bm.deltaMin, GetNPlanOptions{NumSimulations: 100, Rsrc: newDefaultRandSource()})
+			}
+		})
+	}
+}
+
 // Downloaded from https://github.com/ManyLabsOpenScience/ManyLabs2/blob/master/OSFdata/Moral%20Typecasting%20(Gray%20%26%20Wegner%2C%202009)/Gray.1/Global/Data/Gray_1_study_global_include_all_CLEAN_CASE.csv
 //
 //go:embed testdata/Gray_1_study_global_include_all_CLEAN_CASE.csv
@@ -570,6 +2217,48 @@ func splitGray(data []grayCase) ([]float64, []float64) {
 	return x, y
 }
 
+func FuzzEValue(f *testing.F) {
+	f.Add([]byte{}, uint8(0))
+	f.Add(binary.LittleEndian.AppendUint64(nil, math.Float64bits(1)), uint8(1))
+	seed := []byte{}
+	for _, v := range []float64{1, 2, 3, 4, 2, 4, 6, 9} {
+		seed = binary.LittleEndian.AppendUint64(seed, math.Float64bits(v))
+	}
+	f.Add(seed, uint8(4))
+	f.Add(seed[:4*8], uint8(2))
+	f.Fuzz(func(t *testing.T, data []byte, split uint8) {
+		var xy []float64
+		for ; len(data) >= 8; data = data[8:] {
+			xy = append(xy, math.Float64frombits(binary.LittleEndian.Uint64(data)))
+		}
+		i := min(int(split), len(xy))
+		x, y := xy[:i], xy[i:]
+
+		// Outputs carry no evidence if there are too few observations, and are NaN if and only if the t-statistic is undefined.
+		p := &Mom{G: 0.1339827}
+		if !TStat(x, y, 0).sufficient() {
+			if e := p.EValue(x, y); e != 1 {
+				t.Errorf("EValue(%v, %v) = %f, want 1", x, y, e)
+			}
+			if ci := p.CI(x, y, 0.05); !math.IsInf(ci[0], -1) || !math.IsInf(ci[1], 1) {
+				t.Errorf("CI(%v, %v) = %v, want [-Inf, +Inf]", x, y, ci)
+			}
+			return
+		}
+		defined := TStat(x, y, 0).defined()
+		if e := p.EValue(x, y); math.IsNaN(e) == defined || e < 0 {
+			t.Errorf("EValue(%v, %v) = %f", x, y, e)
+		}
+		ci := p.CI(x, y, 0.05)
+		if defined && !(ci[0] <= ci[1]) {
+			t.Errorf("CI(%v, %v) = %v", x, y, ci)
+		}
+		if !defined && !(math.IsNaN(ci[0]) && math.IsNaN(ci[1])) {
+			t.Errorf("CI(%v, %v) = %v, want NaN", x, y, ci)
+		}
+	})
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	log.SetFlags(log.Lmicroseconds | log.Llongfile | log.LstdFlags)
@@ -0,0 +1,30 @@
+package evalue
+
+import (
+	"math"
+)
+
+// GROBound returns the largest Bayes factor of the two sample data over all priors of the effect size.
+//
+// The e-value of a mom e-process is the Bayes factor of the t-statistic under a non-local moment prior of the noncentrality parameter.
+// Since a Bayes factor averages the likelihood ratio over its prior, no prior yields a Bayes factor larger than the likelihood ratio at the maximum likelihood noncentrality parameter.
+// GROBound returns this maximal likelihood ratio, and thus EValue(x, y) <= GROBound(x, y) for any G.
+// The gap between the two is the price the e-value pays for being a valid test at all times, rather than a prior chosen in hindsight.
+//
+// If both groups are constant, GROBound returns 1 if their means are equal, and +Inf otherwise.
+func (p *Mom) GROBound(x, y []float64) float64 {
+	t := TStat(x, y, 0)
+	if t.Sp == 0 {
+		if t.Mean1 == t.Mean2 {
+			return 1
+		}
+		return math.Inf(1)
+	}
+
+	logNull := NoncentralT{Nu: t.Nu}.LogProb(t.T)
+	logRatio := func(mu float64) float64 { return NoncentralT{Nu: t.Nu, Mu: mu}.LogProb(t.T) - logNull }
+	// The maximum likelihood noncentrality parameter has the sign of T, and is at most a few times larger since the mode of the noncentral t-distribution is shrunk towards zero.
+	hi := 4*t.T + math.Copysign(1, t.T)
+	mu := maximizeGolden(logRatio, min(0, hi), max(0, hi))
+	return math.Exp(max(0, logRatio(mu)))
+}
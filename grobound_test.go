@@ -0,0 +1,39 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestGROBound(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	for i, n := range []int{10, 20, 30, 60, len(data)} {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			x, y := splitGray(data[:n])
+			bound := (&Mom{}).GROBound(x, y)
+			if math.IsNaN(bound) || bound < 1 {
+				t.Fatalf("unexpected bound %f", bound)
+			}
+			for _, g := range []float64{0.01, 0.1339827, 1, 10} {
+				if e := (&Mom{G: g}).EValue(x, y); e > bound*(1+1e-6) {
+					t.Errorf("n %d, g %f: e-value %f exceeds bound %f", n, g, e, bound)
+				}
+			}
+		})
+	}
+}
+
+func TestGROBoundConstant(t *testing.T) {
+	t.Parallel()
+	p := &Mom{G: 0.1339827}
+	if b := p.GROBound([]float64{1, 1}, []float64{1, 1, 1}); b != 1 {
+		t.Errorf("unexpected bound of equal constant groups: got %f want 1", b)
+	}
+	if b := p.GROBound([]float64{1, 1}, []float64{2, 2, 2}); !math.IsInf(b, 1) {
+		t.Errorf("unexpected bound of different constant groups: got %f want +Inf", b)
+	}
+}
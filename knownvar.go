@@ -0,0 +1,33 @@
+package evalue
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// EValueKnownVar returns the e-value of the two sample data, using the known standard deviation sigma of the observations in place of the pooled sample standard deviation Sp.
+// The z-statistic sqrt(nEff)*(mean1-mean2)/sigma replaces the t-statistic, and so the e-value is the limit of EValue for infinitely many degrees of freedom.
+// Without the uncertainty of estimating the variance, it grows faster than EValue early in an experiment.
+// It is valid only if sigma is the true standard deviation, such as one reliably estimated from plenty of historical data.
+// It returns 1 if either group is empty, and NaN if sigma is not positive.
+func (p *Mom) EValueKnownVar(x, y []float64, sigma float64) float64 {
+	if !(sigma > 0) {
+		return math.NaN()
+	}
+	if len(x) == 0 || len(y) == 0 {
+		return 1
+	}
+	nEff := EffectiveSampleSize(len(x), len(y))
+	z := math.Sqrt(nEff) * (stat.Mean(x, nil) - stat.Mean(y, nil)) / sigma
+	return eValueZ(z, nEff, p.G)
+}
+
+// eValueZ returns the e-value of a z-statistic, for the mom e-process with tuning parameter g.
+// As nu tends to infinity, the hypergeometric function of eValueG tends to 1F1(3/2; 1/2; x) = (1+2x)*exp(x), where x = r*z*z/2 and r = nEff*g/(1+nEff*g).
+// The e-value is computed in log space, so that it overflows only if the e-value itself does.
+func eValueZ(z, nEff, g float64) float64 {
+	ng := nEff * g
+	rz2 := ng / (1 + ng) * z * z
+	return math.Exp(-3./2*math.Log1p(ng) + math.Log1p(rz2) + rz2/2)
+}
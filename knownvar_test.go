@@ -0,0 +1,92 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestEValueZ(t *testing.T) {
+	t.Parallel()
+	// The e-value of a z-statistic is the limit of that of a t-statistic with many degrees of freedom.
+	for _, g := range []float64{0.01, 0.1339827, 1} {
+		for _, z := range []float64{0, 0.5, 2.244057, 5} {
+			for _, nEff := range []float64{0.5, 8, 30} {
+				if e, want := eValueZ(z, nEff, g), eValueG(z, 1e9, nEff, g); !scalar.EqualWithinRel(e, want, 1e-6) {
+					t.Errorf("g %f z %f nEff %f: got %f want %f", g, z, nEff, e, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEValueKnownVar(t *testing.T) {
+	t.Parallel()
+	const alpha, n = 0.05, 100
+	p := NewMom(0.5)
+	tests := []struct {
+		delta float64
+		// rejected and stopT are the rejection rate and the mean stopping time of EValueKnownVar with the true sigma.
+		rejected float64
+		stopT    float64
+		// rejectedT and stopTT are those of the t-based EValue on the same data.
+		rejectedT float64
+		stopTT    float64
+	}{
+		{delta: 0, rejected: 0.028, stopT: 98.14, rejectedT: 0.031, stopTT: 98.022},
+		{delta: 0.5, rejected: 0.802, stopT: 58.057, rejectedT: 0.805, stopTT: 59.125},
+		{delta: 1, rejected: 1, stopT: 18.818, rejectedT: 1, stopTT: 21.162},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			const numSimulations = 1000
+			rnd := rand.New(rand.NewPCG(uint64(i), 1))
+			var rejected, rejectedT, stopT, stopTT float64
+			for range numSimulations {
+				x, y := GaussianGen{Delta: test.delta}.Generate(rnd, n)
+				known, tBased := n, n
+				for j := 1; j <= n; j++ {
+					if known == n && p.EValueKnownVar(x[:j], y[:j], 1) > 1/alpha {
+						known = j
+						rejected++
+					}
+					if tBased == n && p.EValue(x[:j], y[:j]) > 1/alpha {
+						tBased = j
+						rejectedT++
+					}
+				}
+				stopT += float64(known)
+				stopTT += float64(tBased)
+			}
+			rejected, rejectedT = rejected/numSimulations, rejectedT/numSimulations
+			stopT, stopTT = stopT/numSimulations, stopTT/numSimulations
+			if rejected != test.rejected || rejectedT != test.rejectedT {
+				t.Errorf("unexpected rejection rates: got %f %f want %f %f", rejected, rejectedT, test.rejected, test.rejectedT)
+			}
+			if !scalar.EqualWithinRel(stopT, test.stopT, 1e-12) || !scalar.EqualWithinRel(stopTT, test.stopTT, 1e-12) {
+				t.Errorf("unexpected mean stopping times: got %f %f want %f %f", stopT, stopTT, test.stopT, test.stopTT)
+			}
+
+			// The known variance remains valid under the null hypothesis, and rejects earlier under the alternative.
+			if test.delta == 0 && rejected > alpha {
+				t.Errorf("Type I error %f exceeds %f", rejected, alpha)
+			}
+			if test.delta > 0 && !(stopT < stopTT) {
+				t.Errorf("known variance does not reject earlier: %f %f", stopT, stopTT)
+			}
+		})
+	}
+
+	if e := p.EValueKnownVar(nil, []float64{1}, 1); e != 1 {
+		t.Errorf("got %f want 1", e)
+	}
+	for _, sigma := range []float64{0, -1, math.NaN()} {
+		if e := p.EValueKnownVar([]float64{1, 2}, []float64{3, 4}, sigma); !math.IsNaN(e) {
+			t.Errorf("unexpected e-value of sigma %f: got %f want NaN", sigma, e)
+		}
+	}
+}
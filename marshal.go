@@ -0,0 +1,160 @@
+package evalue
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var errInvalidEncoding = errors.New("evalue: invalid binary encoding")
+
+const (
+	momSize            = 2 * 8
+	momStreamSize      = momSize + 6*8
+	sequentialTestSize = 8*8 + momStreamSize
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (p *Mom) MarshalBinary() ([]byte, error) {
+	return p.appendBinary(make([]byte, 0, momSize)), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (p *Mom) UnmarshalBinary(data []byte) error {
+	if len(data) != momSize {
+		return errInvalidEncoding
+	}
+	p.readBinary(data)
+	return nil
+}
+
+func (p *Mom) appendBinary(b []byte) []byte {
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.G))
+	return binary.LittleEndian.AppendUint64(b, uint64(p.Prec))
+}
+
+func (p *Mom) readBinary(data []byte) {
+	p.G = math.Float64frombits(binary.LittleEndian.Uint64(data))
+	p.Prec = uint(binary.LittleEndian.Uint64(data[8:]))
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The encoding contains the mom e-process and the running means and squared deviations of the stream.
+func (s *MomStream) MarshalBinary() ([]byte, error) {
+	return s.appendBinary(make([]byte, 0, momStreamSize)), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *MomStream) UnmarshalBinary(data []byte) error {
+	if len(data) != momStreamSize {
+		return errInvalidEncoding
+	}
+	s.readBinary(data)
+	return nil
+}
+
+func (s *MomStream) appendBinary(b []byte) []byte {
+	b = s.p.appendBinary(b)
+	for i := range s.n {
+		b = binary.LittleEndian.AppendUint64(b, uint64(s.n[i]))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(s.mean[i]))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(s.m2[i]))
+	}
+	return b
+}
+
+func (s *MomStream) readBinary(data []byte) {
+	s.p = &Mom{}
+	s.p.readBinary(data)
+	data = data[momSize:]
+	for i := range s.n {
+		s.n[i] = int(binary.LittleEndian.Uint64(data))
+		s.mean[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8:]))
+		s.m2[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[16:]))
+		data = data[24:]
+	}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The encoding allows an in-flight experiment to be checkpointed and resumed later.
+func (st *SequentialTest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, sequentialTestSize)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(st.alpha))
+	b = binary.LittleEndian.AppendUint64(b, uint64(st.minSamples))
+	b = binary.LittleEndian.AppendUint64(b, uint64(int64(st.stopT)))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(st.futilityThreshold))
+	b = binary.LittleEndian.AppendUint64(b, uint64(st.futilityWindow))
+	b = binary.LittleEndian.AppendUint64(b, uint64(st.numBelow))
+	var futile uint64
+	if st.futile {
+		futile = 1
+	}
+	b = binary.LittleEndian.AppendUint64(b, futile)
+	var positiveVariance uint64
+	if st.positiveVariance {
+		positiveVariance = 1
+	}
+	b = binary.LittleEndian.AppendUint64(b, positiveVariance)
+	b = st.stream.appendBinary(b)
+
+	// The spending schedule, if any, follows the fixed size part.
+	if len(st.looks) > 0 {
+		b = binary.LittleEndian.AppendUint64(b, uint64(len(st.looks)))
+		b = binary.LittleEndian.AppendUint64(b, uint64(st.look))
+		for i, n := range st.looks {
+			b = binary.LittleEndian.AppendUint64(b, uint64(n))
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(st.lookThresholds[i]))
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (st *SequentialTest) UnmarshalBinary(data []byte) error {
+	if len(data) < sequentialTestSize {
+		return errInvalidEncoding
+	}
+	looks, lookThresholds, look, err := readSpendingSchedule(data[sequentialTestSize:])
+	if err != nil {
+		return err
+	}
+	st.looks, st.lookThresholds, st.look = looks, lookThresholds, look
+	st.alpha = math.Float64frombits(binary.LittleEndian.Uint64(data))
+	st.minSamples = int(binary.LittleEndian.Uint64(data[8:]))
+	st.stopT = int(int64(binary.LittleEndian.Uint64(data[16:])))
+	st.futilityThreshold = math.Float64frombits(binary.LittleEndian.Uint64(data[24:]))
+	st.futilityWindow = int(binary.LittleEndian.Uint64(data[32:]))
+	st.numBelow = int(binary.LittleEndian.Uint64(data[40:]))
+	st.futile = binary.LittleEndian.Uint64(data[48:]) != 0
+	st.positiveVariance = binary.LittleEndian.Uint64(data[56:]) != 0
+	st.stream = &MomStream{}
+	st.stream.readBinary(data[64:sequentialTestSize])
+	return nil
+}
+
+// readSpendingSchedule decodes the looks, their thresholds, and the index of the next look of a SequentialTest, which are empty for a test without a spending schedule.
+func readSpendingSchedule(data []byte) ([]int, []float64, int, error) {
+	if len(data) == 0 {
+		return nil, nil, 0, nil
+	}
+	if len(data) < 16 {
+		return nil, nil, 0, errInvalidEncoding
+	}
+	n := binary.LittleEndian.Uint64(data)
+	look := int(binary.LittleEndian.Uint64(data[8:]))
+	data = data[16:]
+	if n == 0 || uint64(len(data)) != 16*n || look < 0 || look > int(n) {
+		return nil, nil, 0, errInvalidEncoding
+	}
+	looks, thresholds := make([]int, n), make([]float64, n)
+	for i := range looks {
+		looks[i] = int(binary.LittleEndian.Uint64(data))
+		thresholds[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8:]))
+		data = data[16:]
+		// The looks of a SequentialTest are strictly increasing and positive.
+		if looks[i] <= 0 || (i > 0 && looks[i] <= looks[i-1]) {
+			return nil, nil, 0, errInvalidEncoding
+		}
+	}
+	return looks, thresholds, look, nil
+}
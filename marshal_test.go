@@ -0,0 +1,115 @@
+package evalue
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func TestMomMarshalBinary(t *testing.T) {
+	t.Parallel()
+	p := NewMom(0.5176537)
+	p.Prec = 128
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var restored Mom
+	if err := restored.UnmarshalBinary(b); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if restored != *p {
+		t.Errorf("unexpected restored mom: got %+v want %+v", restored, *p)
+	}
+
+	if err := restored.UnmarshalBinary(b[:3]); err == nil {
+		t.Errorf("expected error for truncated data")
+	}
+}
+
+func TestSequentialTestMarshalBinary(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	push := func(st *SequentialTest, data []grayCase) {
+		for _, d := range data {
+			group := 2
+			if d.factor == adultHarmsBaby {
+				group = 1
+			}
+			st.Push(group, float64(d.variable))
+		}
+	}
+
+	// Run the experiment without interruption.
+	p := NewMom(0.5176537)
+	const alpha = 0.05
+	opt := SequentialTestOptions{RequirePositiveVariance: true}
+	uninterrupted := NewSequentialTest(p, alpha, opt)
+	push(uninterrupted, data)
+
+	// Checkpoint the experiment midway, and resume it from the checkpoint.
+	interrupted := NewSequentialTest(p, alpha, opt)
+	push(interrupted, data[:15])
+	b, err := interrupted.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var resumed SequentialTest
+	if err := resumed.UnmarshalBinary(b); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	push(&resumed, data[15:])
+
+	if resumed.StopT() != uninterrupted.StopT() {
+		t.Errorf("unexpected stopping time: got %d want %d", resumed.StopT(), uninterrupted.StopT())
+	}
+	if resumed.positiveVariance != uninterrupted.positiveVariance {
+		t.Errorf("unexpected positiveVariance: got %t want %t", resumed.positiveVariance, uninterrupted.positiveVariance)
+	}
+	if resumed.EValue() != uninterrupted.EValue() {
+		t.Errorf("unexpected e-value: got %f want %f", resumed.EValue(), uninterrupted.EValue())
+	}
+}
+
+func TestSequentialTestMarshalBinarySpendingSchedule(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	push := func(st *SequentialTest, data []grayCase) {
+		for _, d := range data {
+			group := 2
+			if d.factor == adultHarmsBaby {
+				group = 1
+			}
+			st.Push(group, float64(d.variable))
+		}
+	}
+
+	opt := SequentialTestOptions{SpendingSchedule: SpendingSchedule{Looks: []int{20, 40, 60, 80, 100, 120}}}
+	uninterrupted := NewSequentialTest(NewMom(0.5176537), 0.05, opt)
+	push(uninterrupted, data)
+
+	interrupted := NewSequentialTest(NewMom(0.5176537), 0.05, opt)
+	push(interrupted, data[:50])
+	b, err := interrupted.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var resumed SequentialTest
+	if err := resumed.UnmarshalBinary(b); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	push(&resumed, data[50:])
+	if resumed.StopT() != uninterrupted.StopT() {
+		t.Errorf("unexpected stopping time: got %d want %d", resumed.StopT(), uninterrupted.StopT())
+	}
+
+	if err := resumed.UnmarshalBinary(b[:len(b)-8]); err == nil {
+		t.Errorf("expected error for truncated data")
+	}
+	// The encoding ends with the six looks and their thresholds, whose looks must be strictly increasing.
+	corrupted := slices.Clone(b)
+	binary.LittleEndian.PutUint64(corrupted[len(corrupted)-6*16:], 1000)
+	if err := resumed.UnmarshalBinary(corrupted); err == nil {
+		t.Errorf("expected error for decreasing looks")
+	}
+}
@@ -0,0 +1,220 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestNoncentralTTail(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		nu          float64
+		mu          float64
+		x           float64
+		logCDF      float64
+		logSurvival float64
+	}{
+		// P(T <= 0) = P(Z <= -Mu).
+		{nu: 8, mu: 16, x: 0, logCDF: logNormCDF(-16)},
+		{nu: 8, mu: 1000, x: 0, logCDF: logNormCDF(-1000)},
+		// The central t-distribution in the deep tail.
+		{nu: 5, mu: 0, x: 1e8, logSurvival: math.Log(distuv.StudentsT{Sigma: 1, Nu: 5}.Survival(1e8))},
+		{nu: 200, mu: 0, x: 30, logSurvival: math.Log(distuv.StudentsT{Sigma: 1, Nu: 200}.Survival(30))},
+		// The bulk, where the CDF of gonum is accurate.
+		{nu: 8, mu: 16, x: 16, logCDF: math.Log(distuv.NoncentralT{Nu: 8, Mu: 16}.CDF(16))},
+		{nu: 30, mu: 2, x: 1, logSurvival: math.Log(1 - distuv.NoncentralT{Nu: 30, Mu: 2}.CDF(1))},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			n := NoncentralT{Nu: test.nu, Mu: test.mu}
+			if test.logCDF != 0 {
+				if l := n.LogCDF(test.x); !scalar.EqualWithinRel(l, test.logCDF, 1e-7) {
+					t.Errorf("LogCDF(%f): got %f want %f", test.x, l, test.logCDF)
+				}
+			}
+			if test.logSurvival != 0 {
+				if l := n.LogSurvival(test.x); !scalar.EqualWithinRel(l, test.logSurvival, 1e-7) {
+					t.Errorf("LogSurvival(%f): got %f want %f", test.x, l, test.logSurvival)
+				}
+				if s := n.SurvivalFunction(test.x); !scalar.EqualWithinRel(s, math.Exp(test.logSurvival), 1e-6) {
+					t.Errorf("SurvivalFunction(%f): got %g want %g", test.x, s, math.Exp(test.logSurvival))
+				}
+			}
+		})
+	}
+}
+
+func TestNoncentralTSeries(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		nu          float64
+		mu          float64
+		x           float64
+		logCDF      float64
+		logSurvival float64
+	}{
+		// The deep tails, where the CDF and the survival function of gonum underflow or cancel.
+		{nu: 8, mu: 16, x: 0.5, logCDF: -121.936156391},
+		{nu: 8, mu: 16, x: 100, logSurvival: -12.2739020949},
+		{nu: 5, mu: 3, x: 1e6, logSurvival: -61.6634402935},
+		{nu: 5, mu: 0, x: 1e8, logSurvival: -89.8531474839},
+		{nu: 200, mu: 40, x: 30, logCDF: -17.43826518},
+		// The bulk.
+		{nu: 8, mu: 16, x: 16, logCDF: -0.8292906221, logSurvival: -0.573337257829},
+		{nu: 30, mu: 2, x: 1, logCDF: -1.84112493632, logSurvival: -0.172734302223},
+		{nu: 20, mu: 50, x: 45, logCDF: -1.53379503961, logSurvival: -0.242983394908},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			// The series and the integral over the chi-squared mixture are independent computations, which agree in the deep tails.
+			for _, n := range []NoncentralT{{Nu: test.nu, Mu: test.mu}, {Nu: test.nu, Mu: -test.mu}} {
+				x := test.x
+				logCDF, logSurvival := n.LogCDF(x), n.LogSurvival(x)
+				mixCDF := n.logMix(func(z float64) float64 { return logNormCDF(x*z - n.Mu) })
+				mixSurvival := n.logMix(func(z float64) float64 { return logNormCDF(n.Mu - x*z) })
+				if n.Mu < 0 {
+					// The mirror image of T has the noncentrality -Mu, and its tails are swapped.
+					x = -x
+					logCDF, logSurvival = n.LogSurvival(x), n.LogCDF(x)
+					mixCDF = n.logMix(func(z float64) float64 { return logNormCDF(n.Mu - x*z) })
+					mixSurvival = n.logMix(func(z float64) float64 { return logNormCDF(x*z - n.Mu) })
+				}
+				if test.logCDF != 0 {
+					if !scalar.EqualWithinRel(logCDF, test.logCDF, 1e-10) || !scalar.EqualWithinRel(mixCDF, test.logCDF, 1e-8) {
+						t.Errorf("Mu %f: LogCDF: got %.12g and %.12g by integration want %.12g", n.Mu, logCDF, mixCDF, test.logCDF)
+					}
+				}
+				if test.logSurvival != 0 {
+					if !scalar.EqualWithinRel(logSurvival, test.logSurvival, 1e-10) || !scalar.EqualWithinRel(mixSurvival, test.logSurvival, 1e-8) {
+						t.Errorf("Mu %f: LogSurvival: got %.12g and %.12g by integration want %.12g", n.Mu, logSurvival, mixSurvival, test.logSurvival)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNoncentralTLogCDFUnderflow(t *testing.T) {
+	t.Parallel()
+	n := NoncentralT{Nu: 8, Mu: 16}
+	// The CDF of gonum underflows to 0 below x=0.
+	if c := (distuv.NoncentralT{Nu: n.Nu, Mu: n.Mu}).CDF(-1); c != 0 {
+		t.Fatalf("CDF(-1) = %g no longer underflows", c)
+	}
+	prev := n.LogCDF(0)
+	for _, x := range []float64{-1, -10, -100} {
+		l := n.LogCDF(x)
+		if math.IsInf(l, 0) || math.IsNaN(l) || !(l < prev) {
+			t.Errorf("LogCDF(%f): got %f, previous %f", x, l, prev)
+		}
+		prev = l
+	}
+}
+
+func TestNoncentralTLogProb(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		nu float64
+		mu float64
+		x  float64
+	}{
+		{nu: 5, mu: 0, x: 0.3},
+		{nu: 8, mu: 2, x: 1},
+		{nu: 30, mu: -1.5, x: -3},
+		{nu: 100, mu: 4, x: 6},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			want := math.Log(distuv.NoncentralT{Nu: test.nu, Mu: test.mu}.Prob(test.x))
+			if l := (NoncentralT{Nu: test.nu, Mu: test.mu}).LogProb(test.x); !scalar.EqualWithinRel(l, want, 1e-7) {
+				t.Errorf("LogProb(%f): got %f want %f", test.x, l, want)
+			}
+		})
+	}
+}
+
+func TestNoncentralTCDFLargeMu(t *testing.T) {
+	t.Parallel()
+	// reference integrates over the normal Z instead of the chi-squared V, using P(T <= x) = P(V >= Nu*(Z+Mu)^2/x^2) for Z+Mu > 0 and x > 0.
+	// Z+Mu is positive up to a negligible probability, since Mu is large.
+	reference := func(nu, mu, x float64) float64 {
+		chi2 := distuv.ChiSquared{K: nu}
+		f := func(z float64) float64 {
+			return math.Exp(logNormProb(z)) * chi2.Survival(nu*(z+mu)*(z+mu)/(x*x))
+		}
+		return quad.Fixed(f, -12, 12, 512, nil, 0)
+	}
+	tests := []struct {
+		nu float64
+		mu float64
+	}{
+		{nu: 5, mu: 40},
+		{nu: 10, mu: 45},
+		{nu: 50, mu: 60},
+		{nu: 200, mu: 100},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			n := NoncentralT{Nu: test.nu, Mu: test.mu}
+			var maxErrGonum float64
+			// Probe the bulk of the distribution, whose scale is about Mu/sqrt(2*Nu).
+			scale := test.mu / math.Sqrt(2*test.nu)
+			for _, k := range []float64{-1, -0.5, 0, 0.5, 1, 2} {
+				x := test.mu + k*scale
+				want := reference(test.nu, test.mu, x)
+				if c := n.CDF(x); !scalar.EqualWithinAbsOrRel(c, want, 1e-9, 1e-7) {
+					t.Errorf("CDF(%f): got %f want %f", x, c, want)
+				}
+				maxErrGonum = max(maxErrGonum, math.Abs(distuv.NoncentralT{Nu: test.nu, Mu: test.mu}.CDF(x)-want))
+			}
+			// The Abramowitz-Stegun approximation of gonum is inaccurate in this regime.
+			if maxErrGonum < 1e-3 {
+				t.Errorf("gonum CDF error %g is no longer large", maxErrGonum)
+			}
+		})
+	}
+}
+
+func TestNoncentrality(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alpha    float64
+		beta     float64
+		deltaMin float64
+	}{
+		{alpha: 0.05, beta: 0.2, deltaMin: 0.5},
+		{alpha: 0.01, beta: 0.1, deltaMin: 0.3},
+		{alpha: 0.1, beta: 0.5, deltaMin: 1},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			// The batch sample size is the smallest at which the beta quantile of the t-statistic under the alternative reaches an e-value of 1/alpha.
+			p := NewMom(test.deltaMin)
+			n := GetNPlan(test.alpha, test.beta, test.deltaMin, GetNPlanOptions{NumSimulations: 1}).Batch
+			eValue := func(n int) float64 {
+				nu, nEff := float64(2*n-2), EffectiveSampleSize(n, n)
+				mu := Noncentrality(nEff, test.deltaMin)
+				if d := NoncentralityEffectSize(nEff, mu); !scalar.EqualWithinRel(d, test.deltaMin, 1e-15) {
+					t.Errorf("NoncentralityEffectSize: got %f want %f", d, test.deltaMin)
+				}
+				return p.eValue(distuv.NoncentralT{Nu: nu, Mu: mu}.Quantile(test.beta), nu, nEff)
+			}
+			if e := eValue(n); !(e >= 1/test.alpha) {
+				t.Errorf("e-value %f at the batch sample size %d is below %f", e, n, 1/test.alpha)
+			}
+			if e := eValue(n - 1); !(e < 1/test.alpha) {
+				t.Errorf("e-value %f below the batch sample size %d is not below %f", e, n, 1/test.alpha)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package evalue
+
+import (
+	"math"
+)
+
+// EValueEquivalence returns the e-value for the equivalence of the two sample data.
+// The alternative hypothesis is that the difference between the group means lies inside (-margin, margin).
+// This is the anytime-valid analogue of the two one-sided tests (TOST) procedure:
+// the smaller of the one-sided e-values against mean1-mean2 >= margin and mean1-mean2 <= -margin is returned.
+// Like EValue, it returns 1 if there are too few observations to estimate the variance or both groups are constant, and NaN if the t-statistic is undefined.
+func (p *Mom) EValueEquivalence(x, y []float64, margin float64) float64 {
+	upper := TStat(x, y, margin)
+	lower := TStat(x, y, -margin)
+	if !upper.sufficient() {
+		return 1
+	}
+	if !upper.defined() || !lower.defined() {
+		return math.NaN()
+	}
+	if upper.Sp == 0 {
+		return 1
+	}
+	eUpper := p.eValueGreater(-upper.T, upper.Nu, upper.NEff)
+	eLower := p.eValueGreater(lower.T, lower.Nu, lower.NEff)
+	return min(eUpper, eLower)
+}
+
+// A OneSidedMom is a mom e-process for a directional alternative hypothesis, whose prior is restricted to effect sizes of one sign.
+type OneSidedMom struct {
+	// Mom is the two-sided mom e-process whose prior is restricted.
+	Mom *Mom
+	// Negative sets the alternative hypothesis to mean1 < mean2, rather than mean1 > mean2.
+	Negative bool
+}
+
+// NewOneSidedMom creates a one-sided mom e-process tuned to the minimal effect size deltaMin, like NewMom.
+// Unlike NewMom, the sign of deltaMin is retained as the direction of the alternative hypothesis, which is mean1 < mean2 for a negative deltaMin.
+func NewOneSidedMom(deltaMin float64) *OneSidedMom {
+	return &OneSidedMom{Mom: NewMom(deltaMin), Negative: deltaMin < 0}
+}
+
+// EValue returns the one-sided e-value of the two sample data.
+// Like Mom.EValue, it returns 1 if there are too few observations to estimate the variance or both groups are constant, and NaN if the t-statistic is undefined.
+// Evidence in the opposite direction of the alternative hypothesis shrinks the e-value towards 0.
+func (p *OneSidedMom) EValue(x, y []float64) float64 {
+	ts := TStat(x, y, 0)
+	if !ts.sufficient() {
+		return 1
+	}
+	if !ts.defined() {
+		return math.NaN()
+	}
+	t := ts.T
+	if p.Negative {
+		t = -t
+	}
+	// Constant groups carry no evidence, like in EValuePhi0.
+	if ts.Sp == 0 {
+		return 1
+	}
+	return p.Mom.eValueGreater(t, ts.Nu, ts.NEff)
+}
+
+// eValueGreater returns the one-sided e-value of a t-statistic, for the alternative hypothesis that the effect size is positive.
+// The mom prior is restricted to positive effect sizes, which splits the series of eValue into its even and odd terms.
+// The even terms sum to eValue, and the odd terms sum to a second hypergeometric function.
+//
+// For a negative t, the odd terms are negative and cancel the even ones, so the sum is dominated by rounding errors once the evidence against a positive effect is strong.
+// The e-value is then evaluated by eValueGreaterNegative instead.
+// For a large positive t, the hypergeometric functions overflow, and the e-value is recovered from the two-sided one,
+// since the one-sided e-values of t and -t average to it.
+func (p *Mom) eValueGreater(t, nu, nEff float64) float64 {
+	g := p.G
+	e1 := math.Pow(1+nEff*g, -3./2)
+	z := t * t / (nu + t*t) * nEff * g / (1 + nEff*g)
+	even := hypergeo((nu+1)/2, 3./2, 1./2, z)
+
+	u := math.Copysign(math.Sqrt(z), t)
+	lg1, _ := math.Lgamma(nu/2 + 1)
+	lg2, _ := math.Lgamma((nu + 1) / 2)
+	odd := 4 / math.Sqrt(math.Pi) * u * math.Exp(lg1-lg2) * hypergeo(nu/2+1, 2, 3./2, z)
+
+	// The sum loses about log10(even/(even+odd)) digits to cancellation.
+	const maxCancellation = 1e4
+	if t < 0 && !((even+odd)*maxCancellation > even) {
+		return eValueGreaterNegative(t, nu, nEff, g)
+	}
+	e := e1 * (even + odd)
+	if t > 0 && (math.IsNaN(e) || math.IsInf(e, 1)) {
+		return 2*p.eValue(t, nu, nEff) - eValueGreaterNegative(-t, nu, nEff, g)
+	}
+	return max(0, e)
+}
+
+// eValueGreaterNegative returns eValueGreater for a negative t without cancellation.
+// Integrating the noncentral t likelihood ratio over the positive half of the mom prior gives
+//
+//	2*(1+nEff*g)^(-3/2) * (1-z)^(-(nu+1)/2) * E[(r*Y+Z)^2; r*Y+Z > 0],
+//
+// where Y follows the chi distribution with nu+1 degrees of freedom, Z is standard normal, z is the argument of the hypergeometric function of eValue, and r = -sqrt(z/(1-z)).
+// All terms are positive, and the expectation is integrated in log space.
+func eValueGreaterNegative(t, nu, nEff, g float64) float64 {
+	ng := nEff * g
+	// Unlike t*t/(nu+t*t), this form of z is exact for an infinite t.
+	z := ng / (1 + ng) / (1 + nu/(t*t))
+	r := -math.Sqrt(z / (1 - z))
+	// logMix integrates over V = Y*Y following the chi-squared distribution with nu+1 degrees of freedom, and passes sqrt(V/(nu+1)).
+	k := math.Sqrt(nu + 1)
+	logE := NoncentralT{Nu: nu + 1}.logMix(func(s float64) float64 { return logNormPartialSecondMoment(-r * k * s) })
+	logE += math.Ln2 - 1.5*math.Log1p(ng) - (nu+1)/2*math.Log1p(-z)
+	return math.Exp(logE)
+}
+
+// logNormPartialSecondMoment returns the log of E[(Z-a)^2; Z > a] for a standard normal Z and a >= 0.
+// It equals sqrt(2/pi)*Hh2(a), where Hh2 is the second Hermite-h function, whose ratios Hh(k)/Hh(k-1) follow a continued fraction.
+// Unlike the closed form (a*a+1)*Phi(-a)-a*phi(a), the continued fraction does not cancel for large a.
+func logNormPartialSecondMoment(a float64) float64 {
+	if a < 2 {
+		return math.Log((a*a+1)*math.Erfc(a/math.Sqrt2)/2 - a*math.Exp(-a*a/2)/math.Sqrt(2*math.Pi))
+	}
+	// The ratios rho(k-1) = Hh(k-1)/Hh(k-2) = 1/(a + k*rho(k)) are evaluated backwards from a deep truncation.
+	const depth = 200
+	var rho, logRatios float64
+	for k := depth; k >= 1; k-- {
+		rho = 1 / (a + float64(k)*rho)
+		if k <= 3 {
+			logRatios += math.Log(rho)
+		}
+	}
+	// Hh(-1) is exp(-a*a/2).
+	return -a*a/2 + logRatios + 0.5*math.Log(2/math.Pi)
+}
+
+// LowerBound returns the one-sided anytime-valid lower confidence bound of the difference between the group means at the significance level alpha.
+// phi0 lies below the bound if and only if the one-sided e-value against mean1-mean2 <= phi0, with the mom prior restricted to positive effect sizes, exceeds 1/alpha.
+// Since the one-sided e-value exceeds the two-sided one for a positive t-statistic, the bound is tighter than the lower end of CI.
+func (p *Mom) LowerBound(x, y []float64, alpha float64) float64 {
+	t := TStat(x, y, 0)
+	return ciOfT(t, p.criticalTGreater(t.Nu, t.NEff, alpha))[0]
+}
+
+// UpperBound returns the one-sided anytime-valid upper confidence bound of the difference between the group means at the significance level alpha.
+// It is the mirror image of LowerBound, for the one-sided e-value against mean1-mean2 >= phi0.
+func (p *Mom) UpperBound(x, y []float64, alpha float64) float64 {
+	t := TStat(x, y, 0)
+	return ciOfT(t, p.criticalTGreater(t.Nu, t.NEff, alpha))[1]
+}
+
+// criticalTGreater is the one-sided analogue of CriticalT, and returns the t-statistic at which eValueGreater equals 1/alpha.
+func (p *Mom) criticalTGreater(nu, nEff, alpha float64) float64 {
+	if !validProbability(alpha) {
+		return math.NaN()
+	}
+	if !(nu > 0 && nEff > 0) {
+		return math.Inf(1)
+	}
+	return solveCriticalT(func(t float64) float64 { return p.eValueGreater(t, nu, nEff) - 1./alpha }, 0)
+}
@@ -0,0 +1,193 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestEValueGreater(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		t    float64
+		nu   float64
+		nEff float64
+		want float64
+	}{
+		// Values computed by numerically integrating the noncentral t likelihood ratio over the positive half of the mom prior.
+		{t: 0, nu: 4, nEff: 2, want: 0.7003883},
+		{t: 1.5, nu: 10, nEff: 5, want: 2.509337},
+		{t: -1.5, nu: 10, nEff: 5, want: 0.1380188},
+		{t: 3, nu: 30, nEff: 15, want: 34.05669},
+		{t: -2, nu: 20, nEff: 8, want: 0.05867216},
+		// Strong evidence against a positive effect, where the even and odd terms cancel.
+		{t: -8, nu: 198, nEff: 50, want: 2.425327004613619e-4},
+		{t: -12, nu: 198, nEff: 50, want: 1.129414881936337e-4},
+		{t: -40, nu: 1998, nEff: 500, want: 1.092653577679118e-7},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			p := &Mom{G: 0.1339827}
+			e := p.eValueGreater(test.t, test.nu, test.nEff)
+			if !scalar.EqualWithinRel(e, test.want, 2e-6) {
+				t.Errorf("unexpected result eValueGreater(%f, %f, %f): got %f want %f", test.t, test.nu, test.nEff, e, test.want)
+			}
+
+			// The two one-sided e-values average to the two-sided one.
+			eLess := p.eValueGreater(-test.t, test.nu, test.nEff)
+			eTwo := p.eValue(test.t, test.nu, test.nEff)
+			if !scalar.EqualWithinRel((e+eLess)/2, eTwo, 1e-12) {
+				t.Errorf("unexpected average of one-sided e-values: got %f want %f", (e+eLess)/2, eTwo)
+			}
+		})
+	}
+}
+
+func TestNewOneSidedMom(t *testing.T) {
+	t.Parallel()
+	// The two-sided mom e-process ignores the sign of deltaMin.
+	if neg, pos := NewMom(-0.5), NewMom(0.5); neg.G != pos.G {
+		t.Errorf("NewMom(-0.5).G %f differs from NewMom(0.5).G %f", neg.G, pos.G)
+	}
+
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	x, y = x[:15], y[:15]
+	neg, pos := NewOneSidedMom(-0.5), NewOneSidedMom(0.5)
+	if neg.Mom.G != pos.Mom.G || !neg.Negative || pos.Negative {
+		t.Errorf("unexpected one-sided mom e-processes %+v %+v", neg, pos)
+	}
+	eNeg, ePos := neg.EValue(x, y), pos.EValue(x, y)
+	if eNeg == ePos {
+		t.Errorf("one-sided e-values do not depend on the sign of deltaMin: %f", eNeg)
+	}
+	// The Carleton data show a positive difference, and the one-sided e-values average to the two-sided one.
+	if !(ePos > eNeg) {
+		t.Errorf("e-value in the direction of the data %f is not larger than %f", ePos, eNeg)
+	}
+	if two := NewMom(0.5).EValue(x, y); !scalar.EqualWithinRel((eNeg+ePos)/2, two, 1e-12) {
+		t.Errorf("unexpected average of one-sided e-values: got %f want %f", (eNeg+ePos)/2, two)
+	}
+	// Swapping the groups swaps the directions.
+	if e := neg.EValue(y, x); !scalar.EqualWithinRel(e, ePos, 1e-12) {
+		t.Errorf("unexpected e-value of swapped groups: got %f want %f", e, ePos)
+	}
+
+	constant := []float64{3, 3, 3}
+	if e := pos.EValue([]float64{4, 4, 4}, constant); e != 1 {
+		t.Errorf("got %f want 1", e)
+	}
+	if e := neg.EValue([]float64{4, 4, 4}, constant); e != 1 {
+		t.Errorf("got %f want 1", e)
+	}
+	if e := pos.EValue(x[:1], y[:1]); e != 1 {
+		t.Errorf("got %f want 1", e)
+	}
+}
+
+func TestOneSidedMomOppositeDirection(t *testing.T) {
+	t.Parallel()
+	// The data show strong evidence that mean1 > mean2, against the alternative hypothesis mean1 < mean2 of x and y swapped.
+	x, y := GaussianGen{Delta: 1}.Generate(rand.New(rand.NewPCG(1, 1)), 200)
+	p := NewOneSidedMom(0.5)
+	if e := p.EValue(y, x); !(0 <= e && e < 1) {
+		t.Errorf("opposite direction: got %g want in [0, 1)", e)
+	}
+	if e := p.EValue(x, y); !(e > 1e6) {
+		t.Errorf("same direction: got %g want large", e)
+	}
+
+	// The one-sided e-value is non-negative and at most twice the two-sided one.
+	for _, n := range []float64{5, 50, 500, 5000} {
+		nu, nEff := 2*n-2, n/2
+		for tt := -60.; tt <= 60; tt += 0.5 {
+			e := p.Mom.eValueGreater(tt, nu, nEff)
+			if two := p.Mom.eValue(tt, nu, nEff); !(0 <= e && e <= 2*two*(1+1e-12)) {
+				t.Errorf("n %f t %f: got %g want in [0, %g]", n, tt, e, 2*two)
+			}
+		}
+	}
+}
+
+func TestEValueEquivalence(t *testing.T) {
+	t.Parallel()
+	rsrc := rand.NewChaCha8([32]byte{0x3c, 0x11, 0x8a, 0x08, 0x83, 0x15, 0x07, 0x19, 0x64, 0x7a, 0x64, 0x5f, 0x71, 0x7e, 0x07, 0x01, 0xd9, 0x80, 0x61, 0xed, 0xce, 0xaa, 0x4e, 0xf2, 0x2f, 0x36, 0xb5, 0x18, 0x82, 0x85, 0x1c, 0x24})
+
+	const alpha = 0.05
+	const margin = 0.5
+	const numSamples = 500
+	const sampleLen = 200
+	tests := []struct {
+		delta float64
+		want  func(rejectRate float64) bool
+	}{
+		// The true difference lies exactly at the margin, which is the least favorable null.
+		{delta: margin, want: func(r float64) bool { return r <= alpha }},
+		// The groups are identical, so equivalence should be established most of the time.
+		{delta: 0, want: func(r float64) bool { return r > 0.5 }},
+	}
+	for i, test := range tests {
+		p := NewMom(0.5)
+		data := normData(rsrc, test.delta, numSamples, sampleLen)
+		var rejects float64
+		for _, sample := range data {
+			for n := 2; n <= sampleLen; n++ {
+				if p.EValueEquivalence(sample[1][:n], sample[0][:n], margin) > 1./alpha {
+					rejects++
+					break
+				}
+			}
+		}
+		if rate := rejects / numSamples; !test.want(rate) {
+			t.Errorf("%d: unexpected rejection rate %f for delta %f", i, rate, test.delta)
+		}
+	}
+
+	// Groups far apart relative to the margin are evidence against equivalence.
+	x, y := GaussianGen{Delta: 5}.Generate(rand.New(rand.NewPCG(1, 1)), 100)
+	if e := NewMom(0.5).EValueEquivalence(x, y, 0.1); !(0 <= e && e < 1) {
+		t.Errorf("groups 5 apart with margin 0.1: got %g want in [0, 1)", e)
+	}
+	// Too few observations to estimate the variance.
+	if e := NewMom(0.5).EValueEquivalence([]float64{1}, []float64{2}, 0.1); e != 1 {
+		t.Errorf("single observations: got %f want 1", e)
+	}
+}
+
+func TestOneSidedBounds(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := NewMom(0.5176537)
+	for _, alpha := range []float64{0.05, 0.2} {
+		lower, upper := p.LowerBound(x, y, alpha), p.UpperBound(x, y, alpha)
+		ci := p.CI(x, y, alpha)
+		if !(ci[0] < lower && lower < upper && upper < ci[1]) {
+			t.Errorf("alpha %f: one-sided bounds [%f, %f] are not inside %v", alpha, lower, upper, ci)
+		}
+
+		// A bound excludes phi0 if and only if the one-sided test rejects it.
+		for _, d := range []float64{-0.5, -0.01, 0.01, 0.5} {
+			phi0 := lower + d
+			ts := TStat(x, y, phi0)
+			if rejects := p.eValueGreater(ts.T, ts.Nu, ts.NEff) > 1/alpha; rejects != (phi0 < lower) {
+				t.Errorf("alpha %f: lower bound %f phi0 %f rejects %t", alpha, lower, phi0, rejects)
+			}
+			phi0 = upper + d
+			ts = TStat(x, y, phi0)
+			if rejects := p.eValueGreater(-ts.T, ts.Nu, ts.NEff) > 1/alpha; rejects != (phi0 > upper) {
+				t.Errorf("alpha %f: upper bound %f phi0 %f rejects %t", alpha, upper, phi0, rejects)
+			}
+		}
+	}
+
+	// Too few observations yield trivial bounds.
+	if l, u := p.LowerBound([]float64{1}, []float64{2}, 0.05), p.UpperBound([]float64{1}, []float64{2}, 0.05); !(math.IsInf(l, -1) && math.IsInf(u, 1)) {
+		t.Errorf("unexpected bounds %f %f", l, u)
+	}
+}
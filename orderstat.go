@@ -0,0 +1,111 @@
+package evalue
+
+import (
+	"math"
+)
+
+// A stopTCounter maintains the order statistics of stopping times as they stream in, without sorting them.
+// Stopping times are positive integers bounded by the batch sample size, so their counts are kept in Fenwick trees indexed by the stopping time.
+// Adding a stopping time and querying a quantile both take O(log T), where T is the largest stopping time.
+type stopTCounter struct {
+	// n is the number of stopping times, including notStopped.
+	n int
+	// freq is the number of times each stopping time occurs.
+	freq []int
+	// counts and sums are the Fenwick trees of the counts and sums of the stopping times.
+	counts []int
+	sums   []int
+}
+
+// add adds the stopping time t, which is either positive or notStopped.
+func (c *stopTCounter) add(t int) {
+	c.n++
+	if t == notStopped {
+		return
+	}
+	if t >= len(c.freq) {
+		c.grow(t)
+	}
+	c.freq[t]++
+	for i := t; i < len(c.counts); i += i & -i {
+		c.counts[i]++
+		c.sums[i] += t
+	}
+}
+
+// grow enlarges the trees to hold the stopping time t, and rebuilds them from freq.
+func (c *stopTCounter) grow(t int) {
+	size := max(2*len(c.freq), t+1)
+	freq := make([]int, size)
+	copy(freq, c.freq)
+	c.freq = freq
+	c.counts, c.sums = make([]int, size), make([]int, size)
+	for i := 1; i < size; i++ {
+		c.counts[i] += freq[i]
+		c.sums[i] += i * freq[i]
+		if j := i + i&-i; j < size {
+			c.counts[j] += c.counts[i]
+			c.sums[j] += c.sums[i]
+		}
+	}
+}
+
+// prefix returns the number and the sum of the stopping times that are at most t.
+func (c *stopTCounter) prefix(t int) (count, sum int) {
+	for i := min(t, len(c.counts)-1); i > 0; i -= i & -i {
+		count += c.counts[i]
+		sum += c.sums[i]
+	}
+	return count, sum
+}
+
+// kth returns the k-th smallest stopping time counting from 0, with notStopped treated as infinity.
+func (c *stopTCounter) kth(k int) float64 {
+	// Descend the tree to the largest index whose prefix count is at most k.
+	var i int
+	for step := highestPowerOfTwo(len(c.counts) - 1); step > 0; step /= 2 {
+		if j := i + step; j < len(c.counts) && c.counts[j] <= k {
+			i = j
+			k -= c.counts[j]
+		}
+	}
+	if i+1 >= len(c.counts) {
+		return math.Inf(1)
+	}
+	return float64(i + 1)
+}
+
+// quantile returns the p-quantile of the stopping times like stopTQuantile, which linearly interpolates between order statistics.
+// The result equals that of stopTQuantile on the sorted stopping times.
+func (c *stopTCounter) quantile(p float64) int {
+	fidx := p * float64(c.n)
+	i := max(0, int(math.Ceil(fidx))-1)
+	q := c.kth(i)
+	if i > 0 {
+		w := float64(i+1) - fidx
+		q = w*c.kth(i-1) + (1-w)*q
+		// Linear interpolation towards infinity yields NaN when the weight of the infinite end is zero.
+		if math.IsNaN(q) {
+			q = c.kth(i)
+		}
+	}
+	if math.IsInf(q, 1) {
+		return notStopped
+	}
+	return int(math.Ceil(q))
+}
+
+// truncatedMean returns the mean of the stopping times truncated at n, with notStopped treated as infinity.
+func (c *stopTCounter) truncatedMean(n int) float64 {
+	count, sum := c.prefix(n)
+	return float64(sum+n*(c.n-count)) / float64(c.n)
+}
+
+// highestPowerOfTwo returns the highest power of two that is at most n, or 0 if n is not positive.
+func highestPowerOfTwo(n int) int {
+	p := 0
+	for q := 1; q <= n; q *= 2 {
+		p = q
+	}
+	return p
+}
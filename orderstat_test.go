@@ -0,0 +1,67 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestStopTCounter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		n          int
+		maxT       int
+		notStopped float64
+	}{
+		{n: 1, maxT: 10, notStopped: 0},
+		{n: 7, maxT: 3, notStopped: 0.5},
+		{n: 100, maxT: 50, notStopped: 0.1},
+		{n: 1000, maxT: 400, notStopped: 0.3},
+		{n: 333, maxT: 1000, notStopped: 0},
+		{n: 50, maxT: 100, notStopped: 1},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			rnd := rand.New(rand.NewPCG(uint64(i), 1))
+			var c stopTCounter
+			var stopT []int
+			for range test.n {
+				st := 1 + rnd.IntN(test.maxT)
+				if rnd.Float64() < test.notStopped {
+					st = notStopped
+				}
+				stopT = append(stopT, st)
+				c.add(st)
+
+				// The online quantiles match those of the sorted stopping times as they stream in.
+				sorted := sortedStopT(stopT)
+				for _, p := range []float64{0, 0.1, 0.5, 0.8, 0.9, 0.95, 1} {
+					if q, want := c.quantile(p), stopTQuantile(p, sorted); q != want {
+						t.Fatalf("%d stopping times, quantile %f: got %d want %d", len(stopT), p, q, want)
+					}
+				}
+			}
+
+			sorted := sortedStopT(stopT)
+			for _, n := range []int{notStopped, 1, test.maxT / 2, test.maxT} {
+				truncated := make([]float64, len(sorted))
+				for j, st := range sorted {
+					truncated[j] = min(float64(n), st)
+				}
+				if m, want := c.truncatedMean(n), stat.Mean(truncated, nil); !scalar.EqualWithinRel(m, want, 1e-12) {
+					t.Errorf("mean truncated at %d: got %f want %f", n, m, want)
+				}
+			}
+		})
+	}
+
+	var c stopTCounter
+	if q := c.kth(0); !math.IsInf(q, 1) {
+		t.Errorf("empty counter: got %f want +Inf", q)
+	}
+}
@@ -0,0 +1,51 @@
+package evalue
+
+// RetrospectivePower returns the fraction of the e-value trajectories evalues, which ever exceeded 1/alpha.
+// Each trajectory is the e-values recorded at each step of an experiment, such as those of a completed study resampled, or NPlan.EValue.
+// Whereas GetNPlan estimates the power prospectively, RetrospectivePower estimates whether the experiments would have stopped.
+// It returns 0 if there are no trajectories.
+func RetrospectivePower(evalues [][]float64, alpha float64) float64 {
+	if len(evalues) == 0 {
+		return 0
+	}
+	var crossed int
+	for _, trajectory := range evalues {
+		if FirstCrossing(trajectory, alpha) != notStopped {
+			crossed++
+		}
+	}
+	return float64(crossed) / float64(len(evalues))
+}
+
+// PowerCurveDelta returns the simulated power of experiments with early stopping and a sample size of n in the first group, at each true effect size in deltas.
+// The power at a delta is the fraction of simulations on Gaussian data with effect size delta, which reject the null hypothesis within n observations, as in NPlan.Power.
+// The options are those of GetNPlan, except that DataGen and ConvergenceTol are ignored.
+// Like GetNPlan, the mom e-process defaults to NewMom(deltaMin), so that the curve is that of a single design across deltas,
+// and crosses 1-beta near deltaMin for the N of GetNPlan(alpha, beta, deltaMin).
+// It returns nil if n is less than 1, alpha is not in (0, 1) or the options are invalid.
+func PowerCurveDelta(n int, alpha, deltaMin float64, deltas []float64, options ...GetNPlanOptions) []float64 {
+	if n < 1 {
+		return nil
+	}
+	var opt GetNPlanOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	opt.ConvergenceTol = 0
+	// Share the random source across deltas, rather than restarting the default source for each delta.
+	if opt.Rsrc == nil {
+		opt.Rsrc = newDefaultRandSource()
+	}
+
+	powers := make([]float64, 0, len(deltas))
+	for _, delta := range deltas {
+		opt.DataGen = GaussianGen{Delta: delta}
+		// beta only matters for ConvergenceTol, which is disabled.
+		o, err := newGetNPlanOptions(alpha, 0.5, deltaMin, []GetNPlanOptions{opt})
+		if err != nil {
+			return nil
+		}
+		powers = append(powers, simulateNPlan(alpha, 0.5, n, 0, o).Power())
+	}
+	return powers
+}
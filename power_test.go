@@ -0,0 +1,78 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRetrospectivePower(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alpha float64
+		beta  float64
+	}{
+		{alpha: 0.05, beta: 0.2},
+		{alpha: 0.01, beta: 0.1},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			nPlan := GetNPlan(test.alpha, test.beta, 0.5, GetNPlanOptions{NumSimulations: 200})
+			power := RetrospectivePower(nPlan.EValue, test.alpha)
+			if power != nPlan.Power() {
+				t.Errorf("unexpected power: got %f want %f", power, nPlan.Power())
+			}
+			if power < 1-test.beta {
+				t.Errorf("power %f less than %f", power, 1-test.beta)
+			}
+		})
+	}
+
+	evalues := [][]float64{{1, 2, 30}, {1, 0.5}, {25}, {}}
+	if power := RetrospectivePower(evalues, 0.05); power != 0.5 {
+		t.Errorf("unexpected power: got %f want %f", power, 0.5)
+	}
+	if power := RetrospectivePower(nil, 0.05); power != 0 {
+		t.Errorf("unexpected power of no trajectories: got %f", power)
+	}
+}
+
+func TestPowerCurveDelta(t *testing.T) {
+	t.Parallel()
+	const alpha, beta, deltaMin = 0.05, 0.2, 0.5
+	nPlan := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{NumSimulations: 500})
+	deltas := []float64{0, 0.2, 0.4, 0.5, 0.6, 0.8}
+	powers := PowerCurveDelta(nPlan.N, alpha, deltaMin, deltas, GetNPlanOptions{NumSimulations: 500})
+
+	if powers[0] > alpha {
+		t.Errorf("power under the null exceeds alpha: %f", powers[0])
+	}
+	for i := 1; i < len(powers); i++ {
+		if powers[i] <= powers[i-1] {
+			t.Errorf("power is not increasing at delta %f: %v", deltas[i], powers)
+		}
+	}
+	// The power crosses 1-beta near the planned deltaMin.
+	if !(powers[2] < 1-beta && powers[4] > 1-beta) {
+		t.Errorf("power does not cross %f between delta %f and %f: %v", 1-beta, deltas[2], deltas[4], powers)
+	}
+	if p := powers[3]; math.Abs(p-(1-beta)) > 0.05 {
+		t.Errorf("unexpected power at deltaMin: got %f want %f", p, 1-beta)
+	}
+	// The default design is that of GetNPlan, rather than one tuned to each delta.
+	fixed := PowerCurveDelta(nPlan.N, alpha, deltaMin, deltas, GetNPlanOptions{NumSimulations: 500, Mom: NewMom(deltaMin)})
+	if !slices.Equal(fixed, powers) {
+		t.Errorf("default design differs from NewMom(deltaMin): got %v want %v", powers, fixed)
+	}
+
+	if powers := PowerCurveDelta(nPlan.N, 0, deltaMin, deltas); powers != nil {
+		t.Errorf("invalid alpha yields powers %v", powers)
+	}
+	for _, n := range []int{0, -1} {
+		if powers := PowerCurveDelta(n, alpha, deltaMin, deltas); powers != nil {
+			t.Errorf("invalid n %d yields powers %v", n, powers)
+		}
+	}
+}
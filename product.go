@@ -0,0 +1,49 @@
+package evalue
+
+import (
+	"math"
+)
+
+// A ProductAccumulator multiplies e-values, such as those of independent sites in a meta-analysis.
+// The product of independent e-values is itself an e-value.
+// The product is accumulated in log space, so that it does not overflow even for many e-values.
+// The zero value is an empty product, which equals 1.
+type ProductAccumulator struct {
+	logProduct float64
+}
+
+// Add multiplies the product by the e-value e.
+func (a *ProductAccumulator) Add(e float64) {
+	a.logProduct += math.Log(e)
+}
+
+// Log returns the natural logarithm of the product.
+func (a *ProductAccumulator) Log() float64 {
+	return a.logProduct
+}
+
+// Value returns the product, and reports whether it is representable as a float64 without overflow.
+// If the product overflows, Value returns +Inf and false.
+func (a *ProductAccumulator) Value() (float64, bool) {
+	v := math.Exp(a.logProduct)
+	return v, !math.IsInf(v, 1)
+}
+
+// CombineEValues combines the e-values es of independent endpoints, such as a continuous and a binary endpoint of the same study, into one decision.
+// The product of e-values of independent endpoints, whatever their e-processes, is itself an e-value, and so is returned as product.
+// reject reports whether the global null hypothesis, that no endpoint has an effect, is rejected at the significance level alpha, which is decided in log space so that an overflowed product still rejects.
+// An empty es yields a product of 1, which never rejects.
+//
+// Note that for dependent endpoints the product is in general not an e-value, in which case the average of the e-values should be used instead.
+func CombineEValues(es ...float64) (product float64, reject func(alpha float64) bool) {
+	var acc ProductAccumulator
+	for _, e := range es {
+		acc.Add(e)
+	}
+	product, _ = acc.Value()
+	logProduct := acc.Log()
+	reject = func(alpha float64) bool {
+		return validProbability(alpha) && logProduct > -math.Log(alpha)
+	}
+	return product, reject
+}
@@ -0,0 +1,95 @@
+package evalue
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestProductAccumulator(t *testing.T) {
+	t.Parallel()
+	var acc ProductAccumulator
+	if v, ok := acc.Value(); !(v == 1 && ok) {
+		t.Errorf("unexpected empty product: %f %t", v, ok)
+	}
+
+	rnd := rand.New(newDefaultRandSource())
+	naive := 1.
+	var logSum float64
+	for range 1000 {
+		e := 2 + rnd.Float64()
+		acc.Add(e)
+		naive *= e
+		logSum += math.Log(e)
+	}
+	if !math.IsInf(naive, 1) {
+		t.Fatalf("naive product does not overflow: %f", naive)
+	}
+	if l := acc.Log(); math.IsInf(l, 0) || !scalar.EqualWithinRel(l, logSum, 1e-12) {
+		t.Errorf("unexpected log product: got %f want %f", l, logSum)
+	}
+	if v, ok := acc.Value(); ok || !math.IsInf(v, 1) {
+		t.Errorf("overflowed product is reported as representable: %f %t", v, ok)
+	}
+
+	// Small e-values bring the product back into range.
+	for range 1000 {
+		acc.Add(0.4)
+	}
+	if v, ok := acc.Value(); !ok || !scalar.EqualWithinRel(v, math.Exp(logSum+1000*math.Log(0.4)), 1e-9) {
+		t.Errorf("unexpected product: %g %t", v, ok)
+	}
+}
+
+func TestCombineEValues(t *testing.T) {
+	t.Parallel()
+	product, reject := CombineEValues(2, 3)
+	if product != 6 {
+		t.Errorf("unexpected product: %f", product)
+	}
+	if !reject(0.2) || reject(0.1) || reject(0) {
+		t.Errorf("unexpected rejections")
+	}
+	product, reject = CombineEValues(1e200, 1e200)
+	if !math.IsInf(product, 1) || !reject(0.05) {
+		t.Errorf("overflowed product %f does not reject", product)
+	}
+	if product, reject := CombineEValues(); product != 1 || reject(0.5) {
+		t.Errorf("unexpected empty combination %f", product)
+	}
+}
+
+func TestCombineEValuesTypeI(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	const numSims = 2000
+	const n = 50
+	const p0, p1 = 0.5, 0.7
+	rnd := rand.New(newDefaultRandSource())
+	p := NewMom(0.5)
+	var numRejects int
+	for range numSims {
+		// A continuous endpoint with no effect.
+		x, y := GaussianGen{}.Generate(rnd, n)
+		eMom := p.EValue(x, y)
+
+		// An independent binary endpoint with the null success probability p0, tested against p1 by a likelihood ratio.
+		eBernoulli := 1.
+		for range n {
+			if rnd.Float64() < p0 {
+				eBernoulli *= p1 / p0
+			} else {
+				eBernoulli *= (1 - p1) / (1 - p0)
+			}
+		}
+
+		if _, reject := CombineEValues(eMom, eBernoulli); reject(alpha) {
+			numRejects++
+		}
+	}
+	if typeI := float64(numRejects) / numSims; typeI > alpha {
+		t.Errorf("type I error %f exceeds %f", typeI, alpha)
+	}
+}
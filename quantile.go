@@ -0,0 +1,40 @@
+package evalue
+
+import (
+	"math"
+)
+
+// EValueQuantile returns the e-value of the one sample data x, for the null hypothesis that value is the quantile of the population.
+// In other words, the null hypothesis is that an observation is at most value with probability quantile.
+//
+// Like the sign test, only whether each observation is at most value matters, so the e-value is valid for any population.
+// Under the alternative, the probability is given a uniform prior, and the e-value is the resulting Bayes factor, which is a test martingale under the null hypothesis.
+// It returns NaN if quantile does not lie in (0, 1).
+func EValueQuantile(x []float64, quantile, value float64) float64 {
+	if !validProbability(quantile) {
+		return math.NaN()
+	}
+	var k float64
+	for _, v := range x {
+		if v <= value {
+			k++
+		}
+	}
+	n := float64(len(x))
+
+	// The marginal likelihood under the uniform prior is the beta function B(k+1, n-k+1).
+	lg1, _ := math.Lgamma(k + 1)
+	lg2, _ := math.Lgamma(n - k + 1)
+	lg3, _ := math.Lgamma(n + 2)
+	logMarginal := lg1 + lg2 - lg3
+	logNull := xLogY(k, quantile) + xLogY(n-k, 1-quantile)
+	return math.Exp(logMarginal - logNull)
+}
+
+// xLogY returns x*log(y), which is 0 if x is 0.
+func xLogY(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
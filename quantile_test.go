@@ -0,0 +1,52 @@
+package evalue
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestEValueQuantile(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	rnd := rand.New(newDefaultRandSource())
+	// The 0.9 quantile of the exponential distribution with rate 1.
+	exp := distuv.Exponential{Rate: 1, Src: rnd}
+	q90 := exp.Quantile(0.9)
+	sample := func(n int) []float64 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = exp.Rand()
+		}
+		return x
+	}
+
+	// Under the null hypothesis, monitoring the e-value continuously rejects at most at the nominal rate.
+	const numSamples = 1000
+	var rejected int
+	for range numSamples {
+		x := sample(200)
+		for n := 1; n <= len(x); n++ {
+			if EValueQuantile(x[:n], 0.9, q90) > 1./alpha {
+				rejected++
+				break
+			}
+		}
+	}
+	if typeI := float64(rejected) / numSamples; !(typeI <= alpha) {
+		t.Errorf("Type I error %f exceeds alpha %f", typeI, alpha)
+	}
+
+	// The median is not the 0.9 quantile.
+	if e := EValueQuantile(sample(200), 0.9, exp.Quantile(0.5)); !(e > 1./alpha) {
+		t.Errorf("the median is not rejected as the 0.9 quantile: %f", e)
+	}
+
+	for _, quantile := range []float64{0, 1, -0.5, 1.5, math.NaN()} {
+		if e := EValueQuantile([]float64{1, 2, 3}, quantile, 2); !math.IsNaN(e) {
+			t.Errorf("unexpected e-value of quantile %f: got %f want NaN", quantile, e)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package evalue
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// EValueRankSum returns the e-value of the Wilcoxon rank-sum statistic of the two sample data.
+// The rank-sum statistic, standardized by its null mean and variance, takes the place of the t-statistic in EValue.
+// Tied values receive their mid-rank, and the null variance is corrected for ties, so that data with many ties, such as Likert scales, are handled properly.
+//
+// The rank-sum statistic is robust to outliers and heavy tails, but its e-value relies on its asymptotic normality, and so its Type I error guarantee holds only approximately.
+func (p *Mom) EValueRankSum(x, y []float64) float64 {
+	z, n := rankSumZ(x, y)
+	if math.IsNaN(z) {
+		// All values are tied, and so there is no evidence against the null hypothesis.
+		z = 0
+	}
+	return p.eValue(z, n-2, EffectiveSampleSize(len(x), len(y)))
+}
+
+// rankSumZ returns the standardized Wilcoxon rank-sum statistic of x1, and the pooled sample size.
+func rankSumZ(x1, x2 []float64) (float64, float64) {
+	r1, r2 := ranks(x1, x2)
+	n1, n2 := float64(len(x1)), float64(len(x2))
+	n := n1 + n2
+	var w float64
+	for _, r := range r1 {
+		w += r
+	}
+
+	// Correct the variance for ties, where a group of t tied values reduces it by t^3-t.
+	pooled := append(slices.Clone(r1), r2...)
+	slices.Sort(pooled)
+	var ties float64
+	for i := 0; i < len(pooled); {
+		j := i + 1
+		for j < len(pooled) && pooled[j] == pooled[i] {
+			j++
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+	variance := n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1)))
+
+	z := (w - n1*(n+1)/2) / math.Sqrt(variance)
+	return z, n
+}
+
+// ranks returns the ranks of x1 and x2 within their pooled sample.
+// Tied values receive the average of their ranks.
+func ranks(x1, x2 []float64) ([]float64, []float64) {
+	pooled := append(slices.Clone(x1), x2...)
+	idx := make([]int, len(pooled))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(a, b int) int { return cmp.Compare(pooled[a], pooled[b]) })
+
+	r := make([]float64, len(pooled))
+	for i := 0; i < len(idx); {
+		j := i + 1
+		for j < len(idx) && pooled[idx[j]] == pooled[idx[i]] {
+			j++
+		}
+		// Ranks are 1-based, and the average of i+1, ..., j is (i+1+j)/2.
+		for k := i; k < j; k++ {
+			r[idx[k]] = float64(i+1+j) / 2
+		}
+		i = j
+	}
+	return r[:len(x1)], r[len(x1):]
+}
@@ -0,0 +1,89 @@
+package evalue
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestRanks(t *testing.T) {
+	t.Parallel()
+	r1, r2 := ranks([]float64{3, 1, 4, 1}, []float64{5, 9, 2, 6, 5})
+	if want := []float64{4, 1.5, 5, 1.5}; !slices.Equal(r1, want) {
+		t.Errorf("unexpected ranks: got %v want %v", r1, want)
+	}
+	if want := []float64{6.5, 9, 3, 8, 6.5}; !slices.Equal(r2, want) {
+		t.Errorf("unexpected ranks: got %v want %v", r2, want)
+	}
+}
+
+func TestEValueRankSumTies(t *testing.T) {
+	t.Parallel()
+	// naiveRanks breaks ties by order of appearance, instead of assigning mid-ranks.
+	naiveRanks := func(x1, x2 []float64) ([]float64, []float64) {
+		pooled := append(slices.Clone(x1), x2...)
+		idx := make([]int, len(pooled))
+		for i := range idx {
+			idx[i] = i
+		}
+		slices.SortStableFunc(idx, func(a, b int) int { return cmp.Compare(pooled[a], pooled[b]) })
+		r := make([]float64, len(pooled))
+		for rank, i := range idx {
+			r[i] = float64(rank + 1)
+		}
+		return r[:len(x1)], r[len(x1):]
+	}
+
+	// Generate Likert data with only three levels under the null hypothesis.
+	rnd := rand.New(newDefaultRandSource())
+	likert := func(n int) []float64 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = float64(1 + rnd.IntN(3))
+		}
+		return x
+	}
+	const alpha = 0.05
+	const numSamples = 1000
+	p := NewMom(0.5176537)
+	var rejected, naiveRejected int
+	for range numSamples {
+		x, y := likert(50), likert(50)
+		if p.EValueRankSum(x, y) > 1./alpha {
+			rejected++
+		}
+		if p.EValue(naiveRanks(x, y)) > 1./alpha {
+			naiveRejected++
+		}
+	}
+
+	typeI, naiveTypeI := float64(rejected)/numSamples, float64(naiveRejected)/numSamples
+	if !(typeI <= alpha) {
+		t.Errorf("Type I error %f exceeds alpha %f", typeI, alpha)
+	}
+	if !(naiveTypeI > alpha) {
+		t.Errorf("naive Type I error %f does not exceed alpha %f", naiveTypeI, alpha)
+	}
+}
+
+func TestEValueRankSum(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		x    []float64
+		y    []float64
+		want float64
+	}{
+		// All tied.
+		{x: []float64{3, 3, 3}, y: []float64{3, 3, 3}, want: NewMom(1).EValueNull(4, 1.5)},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if e := NewMom(1).EValueRankSum(test.x, test.y); e != test.want {
+				t.Errorf("unexpected EValueRankSum: got %f want %f", e, test.want)
+			}
+		})
+	}
+}
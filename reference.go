@@ -0,0 +1,30 @@
+package evalue
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// EValueVsReference returns the e-value for the null hypothesis that the means of both groups x and y equal the known reference mu0.
+// It is the product of the one sample e-values of x and y against mu0, which is an e-value since the groups are independent.
+// Unlike EValue, it detects groups that differ from the reference by the same amount, and so do not differ from each other.
+func (p *Mom) EValueVsReference(x, y []float64, mu0 float64) float64 {
+	return p.eValueOneSample(x, mu0) * p.eValueOneSample(y, mu0)
+}
+
+// eValueOneSample returns the e-value of the one sample data x, for the null hypothesis that their mean is mu0.
+// The one sample t-statistic sqrt(n)*(mean-mu0)/sd has n-1 degrees of freedom, and its e-value is that of a two sample t-statistic with effective sample size n.
+// Like EValuePhi0, it returns 1 for fewer than two observations, and for constant data, which carry no evidence.
+func (p *Mom) eValueOneSample(x []float64, mu0 float64) float64 {
+	if len(x) < 2 {
+		return 1
+	}
+	n := float64(len(x))
+	mean, sd := stat.MeanStdDev(x, nil)
+	if sd == 0 {
+		return 1
+	}
+	t := math.Sqrt(n) * (mean - mu0) / sd
+	return p.eValue(t, n-1, n)
+}
@@ -0,0 +1,63 @@
+package evalue
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestEValueVsReference(t *testing.T) {
+	t.Parallel()
+	const mu0, alpha = 3, 0.05
+	p := NewMom(0.5)
+	tests := []struct {
+		delta1   float64
+		delta2   float64
+		rejected float64
+	}{
+		{delta1: 0, delta2: 0, rejected: 0.024},
+		{delta1: 0.5, delta2: 0.5, rejected: 0.947},
+		{delta1: 0.5, delta2: 0, rejected: 0.599},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			const numSimulations, n = 1000, 50
+			rnd := rand.New(rand.NewPCG(uint64(i), 1))
+			var rejected int
+			for range numSimulations {
+				x, y := make([]float64, n), make([]float64, n)
+				for j := range n {
+					x[j] = mu0 + test.delta1 + rnd.NormFloat64()
+					y[j] = mu0 + test.delta2 + rnd.NormFloat64()
+				}
+				// Monitor the e-value after each pair of observations.
+				for j := 2; j <= n; j++ {
+					if p.EValueVsReference(x[:j], y[:j], mu0) > 1/alpha {
+						rejected++
+						break
+					}
+				}
+			}
+			if r := float64(rejected) / numSimulations; r != test.rejected {
+				t.Errorf("unexpected rejection rate: got %f want %f", r, test.rejected)
+			}
+			// The Type I error is controlled when both groups have mean mu0.
+			if test.delta1 == 0 && test.delta2 == 0 && float64(rejected)/numSimulations > alpha {
+				t.Errorf("Type I error %f exceeds %f", float64(rejected)/numSimulations, alpha)
+			}
+		})
+	}
+
+	// Constant groups at the reference are no evidence against it.
+	x, y := []float64{mu0, mu0, mu0}, []float64{mu0, mu0}
+	if e := p.EValueVsReference(x, y, mu0); e != 1 {
+		t.Errorf("unexpected e-value of constant groups: got %f want 1", e)
+	}
+	if e := p.EValueVsReference(x, []float64{mu0 + 1, mu0 + 1}, mu0); e != 1 {
+		t.Errorf("unexpected e-value of a constant group off the reference: got %f want 1", e)
+	}
+	if e := p.EValueVsReference(x[:1], nil, mu0); e != 1 {
+		t.Errorf("unexpected e-value of too few observations: got %f want 1", e)
+	}
+}
@@ -0,0 +1,75 @@
+package evalue
+
+// SampleSavings compares the sequential e-value test with the batch p-value test over a collection of studies.
+// Each study consists of the observations of group 1 and group 2.
+//
+// Since the order of observations across the two groups is unknown, they are assumed to arrive alternately, with the surplus of the larger group at the end.
+// Use SampleSavingsLabeled if the order is known.
+func SampleSavings(studies [][2][]float64, alpha, deltaMin float64) (eUsed, pUsed, eRejects, pRejects int) {
+	labeled := make([][]LabeledDatum, 0, len(studies))
+	for _, study := range studies {
+		labeled = append(labeled, interleave(study[0], study[1]))
+	}
+	return SampleSavingsLabeled(labeled, alpha, deltaMin)
+}
+
+// SampleSavingsLabeled compares the sequential e-value test with the batch p-value test over a collection of studies.
+// Each study consists of labeled observations in the order they were collected.
+//
+// The e-value test of the mom e-process tuned to deltaMin looks at the data after each observation, and stops once the e-value exceeds 1/alpha.
+// It waits until each group has at least two observations, and while both groups are constant.
+// The p-value test of the classical t-test looks at all the data of a study once at the end, and rejects if the p-value is less than alpha.
+//
+// eUsed and pUsed are the total number of observations used by the two tests, and eRejects and pRejects are the numbers of studies in which they reject the null hypothesis.
+func SampleSavingsLabeled(studies [][]LabeledDatum, alpha, deltaMin float64) (eUsed, pUsed, eRejects, pRejects int) {
+	p := NewMom(deltaMin)
+	for _, study := range studies {
+		// p-value based test.
+		var x, y []float64
+		for _, d := range study {
+			if d.Group == 1 {
+				x = append(x, d.Value)
+			} else {
+				y = append(y, d.Value)
+			}
+		}
+		pUsed += len(study)
+		if PValueStudent(TStat(x, y, 0)) < alpha {
+			pRejects++
+		}
+
+		// e-value based test.
+		s := NewMomStream(p)
+		used, stopped := 0, false
+		for _, d := range study {
+			s.Push(d.Group, d.Value)
+			used++
+			if min(s.n[0], s.n[1]) < 2 {
+				continue
+			}
+			if s.EValue() > 1./alpha {
+				stopped = true
+				break
+			}
+		}
+		eUsed += used
+		if stopped {
+			eRejects++
+		}
+	}
+	return eUsed, pUsed, eRejects, pRejects
+}
+
+// interleave returns the observations of x and y alternately, with the surplus of the longer one at the end.
+func interleave(x, y []float64) []LabeledDatum {
+	data := make([]LabeledDatum, 0, len(x)+len(y))
+	for i := range max(len(x), len(y)) {
+		if i < len(x) {
+			data = append(data, LabeledDatum{Group: 1, Value: x[i]})
+		}
+		if i < len(y) {
+			data = append(data, LabeledDatum{Group: 2, Value: y[i]})
+		}
+	}
+	return data
+}
@@ -0,0 +1,42 @@
+package evalue
+
+import (
+	"testing"
+)
+
+func TestSampleSavings(t *testing.T) {
+	t.Parallel()
+	// The findings in Section 1.1, Savi Tutorial, see TestSaviTutorial_1_1.
+	labeled := make([][]LabeledDatum, 0, len(grayData))
+	studies := make([][2][]float64, 0, len(grayData))
+	for _, study := range grayData {
+		var data []LabeledDatum
+		for _, d := range study {
+			group := 2
+			if d.factor == adultHarmsBaby {
+				group = 1
+			}
+			data = append(data, LabeledDatum{Group: group, Value: float64(d.variable)})
+		}
+		labeled = append(labeled, data)
+		x, y := splitGray(study)
+		studies = append(studies, [2][]float64{x, y})
+	}
+
+	eUsed, pUsed, eRejects, pRejects := SampleSavingsLabeled(labeled, 0.05, 0.769)
+	if !(pUsed == 8002 && pRejects == 58) {
+		t.Errorf("wrong p-value results %d %d", pUsed, pRejects)
+	}
+	if !(eUsed == 2655 && eRejects == 54) {
+		t.Errorf("wrong e-value results %d %d", eUsed, eRejects)
+	}
+
+	// Without the order of observations, the p-value test is unaffected, whereas the e-value test still saves most of the samples.
+	eUsedI, pUsedI, eRejectsI, pRejectsI := SampleSavings(studies, 0.05, 0.769)
+	if !(pUsedI == pUsed && pRejectsI == pRejects) {
+		t.Errorf("wrong p-value results of unlabeled studies %d %d", pUsedI, pRejectsI)
+	}
+	if !(eUsedI < pUsedI/2 && eRejectsI > eRejects*9/10) {
+		t.Errorf("wrong e-value results of unlabeled studies %d %d", eUsedI, eRejectsI)
+	}
+}
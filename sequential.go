@@ -0,0 +1,181 @@
+package evalue
+
+import (
+	"math"
+)
+
+// A SequentialTest performs an e-value based two sample test with optional stopping.
+type SequentialTest struct {
+	alpha      float64
+	minSamples int
+	// positiveVariance is whether the warm-up lasts until each group has a positive sample variance.
+	positiveVariance bool
+	stream           *MomStream
+	stopT            int
+
+	futilityThreshold float64
+	futilityWindow    int
+	// numBelow is the number of consecutive observations whose e-value is below futilityThreshold.
+	numBelow int
+	futile   bool
+
+	// looks and lookThresholds are the looks of SequentialTestOptions.SpendingSchedule and their e-value thresholds.
+	looks          []int
+	lookThresholds []float64
+	// look is the index of the next look.
+	look int
+}
+
+// SequentialTestOptions are options for NewSequentialTest.
+type SequentialTestOptions struct {
+	// MinSamplesPerGroup is the number of observations each group must have before the test starts.
+	// Until then, the e-value is 1 and the test never stops.
+	// This formalizes a warm-up period, since e-values of tiny groups are wildly variable.
+	MinSamplesPerGroup int
+	// RequirePositiveVariance extends the warm-up until each group has a positive sample variance.
+	// This guards against groups that start with identical observations, such as all identical Likert responses.
+	RequirePositiveVariance bool
+
+	// FutilityThreshold, if positive, stops the test for futility once the e-value stays below it for FutilityWindow consecutive observations.
+	// A persistently tiny e-value is evidence for the null hypothesis, suggesting that an effect is unlikely.
+	// This is a design heuristic to save resources, and does not come with any validity guarantee.
+	FutilityThreshold float64
+	// FutilityWindow is the number of consecutive observations for FutilityThreshold, and defaults to 1.
+	FutilityWindow int
+
+	// SpendingSchedule, if it has looks, turns the test into a group-sequential design that rejects the null hypothesis only at the looks.
+	SpendingSchedule SpendingSchedule
+}
+
+// NewSequentialTest creates a sequential test of the mom e-process p at the significance level alpha.
+// alpha must lie in (0, 1), otherwise the test never rejects the null hypothesis.
+func NewSequentialTest(p *Mom, alpha float64, options ...SequentialTestOptions) *SequentialTest {
+	var opt SequentialTestOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.FutilityWindow <= 0 {
+		opt.FutilityWindow = 1
+	}
+	st := &SequentialTest{
+		alpha:             alpha,
+		minSamples:        opt.MinSamplesPerGroup,
+		positiveVariance:  opt.RequirePositiveVariance,
+		stream:            NewMomStream(p),
+		stopT:             notStopped,
+		futilityThreshold: opt.FutilityThreshold,
+		futilityWindow:    opt.FutilityWindow,
+	}
+	if schedule := opt.SpendingSchedule.normalized(); len(schedule.Looks) > 0 {
+		st.looks = schedule.Looks
+		st.lookThresholds = schedule.thresholds(alpha)
+	}
+	return st
+}
+
+// Push adds the observation v to a group, which must be either 1 or 2, and reports whether the null hypothesis is rejected.
+// Once the null hypothesis is rejected, or the test is stopped for futility, the test stops and ignores further observations.
+func (st *SequentialTest) Push(group int, v float64) bool {
+	if st.Stopped() || st.futile {
+		return st.Stopped()
+	}
+	st.stream.Push(group, v)
+	e := st.EValue()
+	if validProbability(st.alpha) && e > st.threshold() {
+		st.stopT = st.stream.n[0] + st.stream.n[1]
+		return true
+	}
+
+	if e < st.futilityThreshold {
+		st.numBelow++
+	} else {
+		st.numBelow = 0
+	}
+	if st.futilityThreshold > 0 && st.numBelow >= st.futilityWindow {
+		st.futile = true
+	}
+	return false
+}
+
+// threshold returns the e-value above which the null hypothesis is rejected at the current observation, and advances to the next look of the spending schedule.
+func (st *SequentialTest) threshold() float64 {
+	if st.looks == nil {
+		return 1. / st.alpha
+	}
+	if st.look < len(st.looks) && st.stream.n[0]+st.stream.n[1] == st.looks[st.look] {
+		st.look++
+		return st.lookThresholds[st.look-1]
+	}
+	return math.Inf(1)
+}
+
+// Stopped reports whether the null hypothesis is rejected.
+func (st *SequentialTest) Stopped() bool {
+	return st.stopT != notStopped
+}
+
+// Futile reports whether the test is stopped for futility, see SequentialTestOptions.FutilityThreshold.
+func (st *SequentialTest) Futile() bool {
+	return st.futile
+}
+
+// StopT returns the number of observations at which the null hypothesis is rejected, or -1 if it is not rejected.
+func (st *SequentialTest) StopT() int {
+	return st.stopT
+}
+
+// EValue returns the e-value of the test, which is frozen at the stopping time.
+// It returns 1 during the warm-up, see SequentialTestOptions.
+func (st *SequentialTest) EValue() float64 {
+	if min(st.stream.n[0], st.stream.n[1]) < st.minSamples {
+		return 1
+	}
+	if st.positiveVariance && !(st.stream.positiveVariance(0) && st.stream.positiveVariance(1)) {
+		return 1
+	}
+	return st.stream.EValue()
+}
+
+// N1 returns the number of observations of group 1 used by the test.
+func (st *SequentialTest) N1() int {
+	return st.stream.n[0]
+}
+
+// N2 returns the number of observations of group 2 used by the test.
+func (st *SequentialTest) N2() int {
+	return st.stream.n[1]
+}
+
+// NEff returns the effective sample size used by the test.
+func (st *SequentialTest) NEff() float64 {
+	return EffectiveSampleSize(st.N1(), st.N2())
+}
+
+// A LabeledDatum is an observation labeled with its group, which must be either 1 or 2.
+type LabeledDatum struct {
+	Group int
+	Value float64
+}
+
+// A SequentialResult is the result of RunSequential.
+type SequentialResult struct {
+	// StopT is the number of observations at which the null hypothesis is rejected, or -1 if it is not rejected.
+	StopT int
+	// EValue is the e-value after each observation, up to and including the stopping time.
+	EValue []float64
+}
+
+// RunSequential performs a sequential test on data in order, with the mom e-process tuned to the minimal effect size deltaMin at the significance level alpha.
+func RunSequential(data []LabeledDatum, alpha, deltaMin float64) SequentialResult {
+	st := NewSequentialTest(NewMom(deltaMin), alpha)
+	res := SequentialResult{EValue: make([]float64, 0, len(data))}
+	for _, d := range data {
+		stopped := st.Push(d.Group, d.Value)
+		res.EValue = append(res.EValue, st.EValue())
+		if stopped {
+			break
+		}
+	}
+	res.StopT = st.StopT()
+	return res
+}
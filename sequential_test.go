@@ -0,0 +1,177 @@
+package evalue
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestSequentialTestSampleSizes(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	st := NewSequentialTest(NewMom(0.5176537), 0.05)
+	for _, d := range data {
+		group := 2
+		if d.factor == adultHarmsBaby {
+			group = 1
+		}
+		st.Push(group, float64(d.variable))
+	}
+
+	if st.StopT() != 30 {
+		t.Fatalf("unexpected stopping time: got %d want %d", st.StopT(), 30)
+	}
+	x, y := splitGray(data[:st.StopT()])
+	if st.N1() != len(x) || st.N2() != len(y) {
+		t.Errorf("unexpected group sizes: got %d %d want %d %d", st.N1(), st.N2(), len(x), len(y))
+	}
+	if nEff := TStat(x, y, 0).NEff; st.NEff() != nEff {
+		t.Errorf("unexpected NEff: got %f want %f", st.NEff(), nEff)
+	}
+}
+
+func TestSequentialTestMinSamplesPerGroup(t *testing.T) {
+	t.Parallel()
+	// Without a warm-up, the test stops when group 2 has only 4 observations.
+	x := []float64{10, 10.1, 9.9, 10, 10.2, 9.8, 10.1, 9.9}
+	y := []float64{0, 0.1, 0.2, 0.1, -0.1, 0, 0.1, -0.2}
+	push := func(st *SequentialTest) {
+		for i := range x {
+			st.Push(1, x[i])
+			st.Push(2, y[i])
+		}
+	}
+	p := NewMom(1)
+	const alpha = 0.05
+	st := NewSequentialTest(p, alpha)
+	push(st)
+	if st.N2() != 4 {
+		t.Fatalf("unexpected stopping group size without warm-up: got %d want %d", st.N2(), 4)
+	}
+
+	const k = 6
+	st = NewSequentialTest(p, alpha, SequentialTestOptions{MinSamplesPerGroup: k})
+	for i := range x {
+		st.Push(1, x[i])
+		if st.N2() < k && (st.Stopped() || st.EValue() != 1) {
+			t.Errorf("stopped before the minimum is met: %d %d %f", st.N1(), st.N2(), st.EValue())
+		}
+		st.Push(2, y[i])
+		if st.N2() < k && (st.Stopped() || st.EValue() != 1) {
+			t.Errorf("stopped before the minimum is met: %d %d %f", st.N1(), st.N2(), st.EValue())
+		}
+	}
+	if !(st.Stopped() && st.N1() == k && st.N2() == k) {
+		t.Errorf("unexpected stopping group sizes: got %d %d want %d %d", st.N1(), st.N2(), k, k)
+	}
+}
+
+func TestSequentialTestFutility(t *testing.T) {
+	t.Parallel()
+	futility := func(gen DataGen) int {
+		rnd := rand.New(newDefaultRandSource())
+		var numFutile int
+		for range 200 {
+			st := NewSequentialTest(NewMom(0.5176537), 0.05, SequentialTestOptions{FutilityThreshold: 0.2, FutilityWindow: 10})
+			x, y := gen.Generate(rnd, 200)
+			for i := range x {
+				st.Push(1, x[i])
+				st.Push(2, y[i])
+			}
+			if st.Futile() {
+				numFutile++
+				if st.Stopped() {
+					t.Errorf("stopped for both futility and rejection")
+				}
+			}
+		}
+		return numFutile
+	}
+
+	null, alternative := futility(GaussianGen{}), futility(GaussianGen{Delta: 0.5176537})
+	if !(null > 2*alternative) {
+		t.Errorf("unexpected number of futility stops: null %d alternative %d", null, alternative)
+	}
+}
+
+func TestSequentialTestInvalidAlpha(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	for _, alpha := range []float64{0, 1, 1.5} {
+		st := NewSequentialTest(NewMom(0.5176537), alpha)
+		for _, d := range data {
+			group := 2
+			if d.factor == adultHarmsBaby {
+				group = 1
+			}
+			if st.Push(group, float64(d.variable)) {
+				t.Fatalf("alpha %f: unexpected rejection at %d", alpha, st.StopT())
+			}
+		}
+	}
+}
+
+func TestRunSequential(t *testing.T) {
+	t.Parallel()
+	gray := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	data := make([]LabeledDatum, 0, len(gray))
+	for _, d := range gray {
+		group := 2
+		if d.factor == adultHarmsBaby {
+			group = 1
+		}
+		data = append(data, LabeledDatum{Group: group, Value: float64(d.variable)})
+	}
+
+	res := RunSequential(data, 0.05, 0.5176537)
+	if res.StopT != 30 {
+		t.Fatalf("unexpected stopping time: got %d want %d", res.StopT, 30)
+	}
+	if len(res.EValue) != res.StopT {
+		t.Fatalf("unexpected number of e-values: got %d want %d", len(res.EValue), res.StopT)
+	}
+	if e := res.EValue[len(res.EValue)-1]; e <= 1./0.05 {
+		t.Errorf("unexpected final e-value %f", e)
+	}
+	for i, e := range res.EValue[:len(res.EValue)-1] {
+		if e > 1./0.05 {
+			t.Errorf("unexpected rejection at %d, e-value %f", i+1, e)
+		}
+	}
+	x, y := splitGray(gray[:res.StopT])
+	if e := NewMom(0.5176537).EValue(x, y); !scalar.EqualWithinRel(res.EValue[len(res.EValue)-1], e, 1e-9) {
+		t.Errorf("unexpected final e-value: got %f want %f", res.EValue[len(res.EValue)-1], e)
+	}
+}
+
+func TestSequentialTestRequirePositiveVariance(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 3, 2, 5, 4, 2}
+	// Group 2 starts with identical Likert responses.
+	y := []float64{4, 4, 4, 4, 3, 5}
+	p := NewMom(0.5)
+	st := NewSequentialTest(p, 0.05, SequentialTestOptions{RequirePositiveVariance: true})
+	stream := NewMomStream(p)
+	for i := range x {
+		st.Push(1, x[i])
+		st.Push(2, y[i])
+		stream.Push(1, x[i])
+		stream.Push(2, y[i])
+
+		e := st.EValue()
+		if i < 4 {
+			if e != 1 {
+				t.Errorf("%d: unexpected e-value during warm-up: got %f want 1", i, e)
+			}
+			continue
+		}
+		if want := p.EValue(x[:i+1], y[:i+1]); !scalar.EqualWithinRel(e, want, 1e-9) {
+			t.Errorf("%d: unexpected e-value: got %f want %f", i, e, want)
+		}
+		if !scalar.EqualWithinRel(stream.EValue(), e, 1e-9) {
+			t.Errorf("%d: unexpected stream e-value: got %f want %f", i, stream.EValue(), e)
+		}
+	}
+}
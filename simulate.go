@@ -0,0 +1,107 @@
+package evalue
+
+import (
+	"context"
+	"crypto/sha256"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// A Procedure is a statistical test that reports whether the two sample data reject the null hypothesis.
+type Procedure func(x, y []float64) bool
+
+// SimulateOptions are options for simulations.
+// Simulations are reproducible, since they default to a fixed random source.
+type SimulateOptions struct {
+	// Rsrc is the random source used in simulations.
+	Rsrc rand.Source
+
+	// Context cancels long simulations, which then return the result of the simulations completed so far.
+	// It defaults to context.Background().
+	Context context.Context
+	// Progress, if non-nil, is called after each simulation with the number of completed simulations and the total number of simulations.
+	Progress func(done, total int)
+}
+
+// newSimulateOptions returns the first of options with defaults filled in.
+func newSimulateOptions(options []SimulateOptions) SimulateOptions {
+	var opt SimulateOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Rsrc == nil {
+		opt.Rsrc = newDefaultRandSource()
+	}
+	if opt.Context == nil {
+		opt.Context = context.Background()
+	}
+	if opt.Progress == nil {
+		opt.Progress = func(int, int) {}
+	}
+	return opt
+}
+
+// SeedFromString derives a seed for rand.NewChaCha8 from an identifier s, such as the name of a study, by hashing it with SHA-256.
+// Simulations seeded by the same identifier are reproducible, for example GetNPlanOptions{Rsrc: rand.NewChaCha8(SeedFromString(id))}, whereas different identifiers yield independent looking random streams.
+func SeedFromString(s string) [32]byte {
+	return sha256.Sum256([]byte(s))
+}
+
+// SimulateContinuationError returns the Type I error of procedure under optional continuation.
+// Each of the numSamples simulated experiments collects numBatches batches of batchSize observations per group under the null hypothesis,
+// and stops as soon as procedure rejects the null hypothesis at the end of a batch.
+// Procedures based on p-values have an inflated Type I error under optional continuation, whereas those based on e-values do not.
+// If the simulations are cancelled, the Type I error is that of the experiments completed so far.
+// It is NaN if no experiment completes, which happens when numSamples is not positive or the context is cancelled from the start.
+func SimulateContinuationError(procedure Procedure, numBatches, batchSize, numSamples int, options ...SimulateOptions) float64 {
+	opt := newSimulateOptions(options)
+
+	rnd := rand.New(opt.Rsrc)
+	sampleLen := numBatches * batchSize
+	x, y := make([]float64, sampleLen), make([]float64, sampleLen)
+	var rejected, done int
+	for done < numSamples && opt.Context.Err() == nil {
+		GaussianGen{}.GenerateInto(rnd, x, y)
+
+		for batch := range numBatches {
+			n := (1 + batch) * batchSize
+			if procedure(x[:n], y[:n]) {
+				rejected++
+				break
+			}
+		}
+
+		done++
+		opt.Progress(done, numSamples)
+	}
+	if done == 0 {
+		return math.NaN()
+	}
+	return float64(rejected) / float64(done)
+}
+
+// CalibrationCheck returns the fraction of random permutations of the group labels of the two sample data, whose e-value exceeds 1/alpha.
+// Permuting the labels destroys any difference between the groups, while keeping the empirical distribution of the data.
+// The fraction thus estimates the Type I error of p on data like x and y, which should not exceed alpha, regardless of whether the data are Gaussian.
+// If the resampling is cancelled, the fraction is that of the permutations completed so far.
+// It is NaN if no permutation completes, which happens when numResamples is not positive or the context is cancelled from the start.
+func CalibrationCheck(x, y []float64, p *Mom, alpha float64, numResamples int, options ...SimulateOptions) float64 {
+	opt := newSimulateOptions(options)
+	rnd := rand.New(opt.Rsrc)
+	pooled := append(slices.Clone(x), y...)
+	var rejected, done int
+	for done < numResamples && opt.Context.Err() == nil {
+		rnd.Shuffle(len(pooled), func(i, j int) { pooled[i], pooled[j] = pooled[j], pooled[i] })
+		if p.EValue(pooled[:len(x)], pooled[len(x):]) > 1./alpha {
+			rejected++
+		}
+
+		done++
+		opt.Progress(done, numResamples)
+	}
+	if done == 0 {
+		return math.NaN()
+	}
+	return float64(rejected) / float64(done)
+}
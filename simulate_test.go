@@ -0,0 +1,221 @@
+package evalue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestSimulateContinuationError(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	pValue := func(x, y []float64) bool {
+		ts := TStat(x, y, 0)
+		p := 2 * (1 - distuv.StudentsT{Sigma: 1, Nu: ts.Nu}.CDF(math.Abs(ts.T)))
+		return p < alpha
+	}
+	eValue := func(x, y []float64) bool {
+		return NewMom(0.51765).EValue(x, y) > 1./alpha
+	}
+	tests := []struct {
+		procedure Procedure
+		want      float64
+	}{
+		// The Type I errors of optional continuation in TestOptionalContinuation.
+		{procedure: pValue, want: 0.147},
+		{procedure: eValue, want: 0.012},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			rsrc := rand.NewChaCha8([32]byte{0xb2, 0x11, 0x8a, 0x08, 0x83, 0x15, 0x07, 0x19, 0x64, 0x7a, 0x64, 0x5f, 0x71, 0x7e, 0x07, 0x01, 0xd9, 0x80, 0x61, 0xed, 0xce, 0xaa, 0x4e, 0xf2, 0x2f, 0x36, 0xb5, 0x18, 0x82, 0x85, 0x1c, 0x24})
+			typeI := SimulateContinuationError(test.procedure, 5, 40, 1000, SimulateOptions{Rsrc: rsrc})
+			if typeI != test.want {
+				t.Errorf("unexpected Type I error: got %f want %f", typeI, test.want)
+			}
+		})
+	}
+}
+
+func TestCalibrationCheck(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := NewMom(0.5176537)
+	tests := []struct {
+		alpha float64
+		want  float64
+	}{
+		{alpha: 0.05, want: 0.004},
+		{alpha: 0.2, want: 0.015},
+		{alpha: 0.5, want: 0.033},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			typeI := CalibrationCheck(x, y, p, test.alpha, 1000)
+			if typeI != test.want {
+				t.Errorf("unexpected Type I error: got %f want %f", typeI, test.want)
+			}
+			// E-values are conservative, and so reject less often than the nominal rate.
+			if !(typeI <= test.alpha) {
+				t.Errorf("Type I error %f exceeds alpha %f", typeI, test.alpha)
+			}
+		})
+	}
+}
+
+func TestSimulateOptionsRsrc(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := NewMom(0.5176537)
+	eValue := func(x, y []float64) bool { return p.EValue(x, y) > 20 }
+	tests := []struct {
+		name     string
+		simulate func(rsrc rand.Source) any
+	}{
+		{name: "GetNPlan", simulate: func(rsrc rand.Source) any {
+			return GetNPlan(0.05, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 100, Rsrc: rsrc}).StopT
+		}},
+		{name: "SimulateContinuationError", simulate: func(rsrc rand.Source) any {
+			return SimulateContinuationError(eValue, 5, 40, 1000, SimulateOptions{Rsrc: rsrc})
+		}},
+		{name: "CalibrationCheck", simulate: func(rsrc rand.Source) any {
+			return CalibrationCheck(x, y, p, 0.5, 1000, SimulateOptions{Rsrc: rsrc})
+		}},
+		{name: "RecommendNumSamples", simulate: func(rsrc rand.Source) any {
+			return RecommendNumSamples(2, 0.05, 0.2, 0.51765, SimulateOptions{Rsrc: rsrc})
+		}},
+		{name: "DataGen", simulate: func(rsrc rand.Source) any {
+			g1, _ := GaussianGen{}.Generate(rand.New(rsrc), 10)
+			return g1
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			a, b := test.simulate(rand.NewPCG(1, 2)), test.simulate(rand.NewPCG(1, 2))
+			if !reflect.DeepEqual(a, b) {
+				t.Errorf("different results with the same seed: %v %v", a, b)
+			}
+			if c := test.simulate(rand.NewPCG(3, 4)); reflect.DeepEqual(a, c) {
+				t.Errorf("same results with different seeds: %v", a)
+			}
+		})
+	}
+}
+
+func TestSimulateCancel(t *testing.T) {
+	t.Parallel()
+	const numCompleted = 300
+	procedure := func(x, y []float64) bool { return NewMom(0.51765).EValue(x, y) > 20 }
+
+	// Cancel from within the progress callback, so that the number of completed simulations is deterministic.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls []int
+	progress := func(done, total int) {
+		if total != 1000 {
+			t.Errorf("unexpected total %d", total)
+		}
+		calls = append(calls, done)
+		if done == numCompleted {
+			cancel()
+		}
+	}
+	typeI := SimulateContinuationError(procedure, 5, 40, 1000, SimulateOptions{Context: ctx, Progress: progress})
+	if len(calls) != numCompleted || calls[0] != 1 || calls[len(calls)-1] != numCompleted {
+		t.Errorf("unexpected progress calls %d", len(calls))
+	}
+
+	// The truncated run equals a run of the completed simulations.
+	if want := SimulateContinuationError(procedure, 5, 40, numCompleted); typeI != want {
+		t.Errorf("got %f want %f", typeI, want)
+	}
+}
+
+func TestSimulateCancelledFromStart(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opt := SimulateOptions{Context: ctx, Progress: func(int, int) { t.Errorf("unexpected progress") }}
+
+	procedure := func(x, y []float64) bool { return NewMom(0.51765).EValue(x, y) > 20 }
+	if typeI := SimulateContinuationError(procedure, 5, 40, 1000, opt); !math.IsNaN(typeI) {
+		t.Errorf("SimulateContinuationError: got %f want NaN", typeI)
+	}
+	x, y := GaussianGen{}.Generate(rand.New(newDefaultRandSource()), 20)
+	if typeI := CalibrationCheck(x, y, NewMom(0.51765), 0.05, 1000, opt); !math.IsNaN(typeI) {
+		t.Errorf("CalibrationCheck: got %f want NaN", typeI)
+	}
+	if n := RecommendNumSamples(2, 0.05, 0.2, 0.51765, opt); n != 0 {
+		t.Errorf("RecommendNumSamples: got %d want 0", n)
+	}
+}
+
+func TestGetNPlanCancel(t *testing.T) {
+	t.Parallel()
+	const alpha, beta, deltaMin = 0.05, 0.2, 0.5
+	const numCompleted = 100
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var numCalls int
+	progress := func(done, total int) {
+		numCalls++
+		if done == numCompleted {
+			cancel()
+		}
+	}
+	nPlan, err := GetNPlanErr(alpha, beta, deltaMin, GetNPlanOptions{Context: ctx, Progress: progress})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	if numCalls != numCompleted || len(nPlan.StopT) != numCompleted {
+		t.Errorf("unexpected number of simulations %d %d", numCalls, len(nPlan.StopT))
+	}
+	want := GetNPlan(alpha, beta, deltaMin, GetNPlanOptions{NumSimulations: numCompleted})
+	if nPlan.N != want.N || nPlan.Mean != want.Mean || nPlan.Batch != want.Batch {
+		t.Errorf("got %d %d %d want %d %d %d", nPlan.N, nPlan.Mean, nPlan.Batch, want.N, want.Mean, want.Batch)
+	}
+
+	// A context cancelled from the start completes no simulation.
+	nPlan, err = GetNPlanErr(alpha, beta, deltaMin, GetNPlanOptions{Context: ctx})
+	if !errors.Is(err, context.Canceled) || len(nPlan.StopT) != 0 || nPlan.Batch != want.Batch {
+		t.Errorf("unexpected %+v %d %d", err, len(nPlan.StopT), nPlan.Batch)
+	}
+}
+
+func TestSeedFromString(t *testing.T) {
+	t.Parallel()
+	if SeedFromString("study A") != SeedFromString("study A") {
+		t.Errorf("the same identifier yields different seeds")
+	}
+	seeds := make(map[[32]byte]string)
+	for _, s := range []string{"", "study A", "study B", "study a", "study A "} {
+		seed := SeedFromString(s)
+		if prev, ok := seeds[seed]; ok {
+			t.Errorf("identifiers %q and %q yield the same seed", prev, s)
+		}
+		seeds[seed] = s
+	}
+
+	// Simulations seeded by the same identifier are reproducible.
+	plan := func(id string) NPlan {
+		return GetNPlan(0.05, 0.2, 0.51765, GetNPlanOptions{NumSimulations: 100, Rsrc: rand.NewChaCha8(SeedFromString(id))})
+	}
+	a, b, c := plan("study A"), plan("study A"), plan("study B")
+	if !slices.Equal(a.StopT, b.StopT) {
+		t.Errorf("the same identifier yields different simulations")
+	}
+	if slices.Equal(a.StopT, c.StopT) {
+		t.Errorf("different identifiers yield the same simulations")
+	}
+}
@@ -0,0 +1,67 @@
+package evalue
+
+import (
+	"math"
+	"slices"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// A SpendingSchedule turns a sequential test into a group-sequential design, which looks at the data only at planned sample sizes, and spends the significance level alpha across the looks.
+// At each look, the null hypothesis is rejected if the e-value exceeds one over the alpha spent at that look.
+// By Markov's inequality at each look and the union bound across looks, the Type I error is at most alpha.
+//
+// The schedule allows comparing the e-value test against classical designs, such as O'Brien-Fleming boundaries.
+// Note that the plain e-value test, which rejects once the e-value exceeds 1/alpha at any time, is valid by Ville's inequality, and so always rejects no later than any spending schedule.
+type SpendingSchedule struct {
+	// Looks are the total numbers of observations at which the test looks at the data.
+	// They are sorted, and duplicate and non-positive looks are ignored.
+	// The test never rejects the null hypothesis between looks, nor after the last look.
+	Looks []int
+	// Spending returns the cumulative significance level spent at the information fraction in (0, 1], which is the sample size of a look relative to the last look.
+	// It must increase to alpha at the fraction 1, and defaults to OBrienFlemingSpending.
+	Spending func(alpha, fraction float64) float64
+}
+
+// normalized returns a copy of s, whose looks are strictly increasing and positive.
+// The looks of the copy do not share memory with those of s.
+func (s SpendingSchedule) normalized() SpendingSchedule {
+	looks := slices.Clone(s.Looks)
+	slices.Sort(looks)
+	looks = slices.Compact(looks)
+	i, _ := slices.BinarySearch(looks, 1)
+	s.Looks = looks[i:]
+	return s
+}
+
+// thresholds returns the e-value thresholds at the looks of the schedule at the significance level alpha.
+// A look that spends no alpha has an infinite threshold.
+func (s SpendingSchedule) thresholds(alpha float64) []float64 {
+	spending := s.Spending
+	if spending == nil {
+		spending = OBrienFlemingSpending
+	}
+	thresholds := make([]float64, len(s.Looks))
+	var spent float64
+	for i, n := range s.Looks {
+		cumulative := spending(alpha, float64(n)/float64(s.Looks[len(s.Looks)-1]))
+		thresholds[i] = math.Inf(1)
+		if cumulative > spent {
+			thresholds[i] = 1 / (cumulative - spent)
+		}
+		spent = max(spent, cumulative)
+	}
+	return thresholds
+}
+
+// OBrienFlemingSpending is the Lan-DeMets spending function of the two-sided O'Brien-Fleming boundary, 2-2Φ(z/sqrt(fraction)) where z is the 1-alpha/2 quantile of the standard normal distribution.
+// It spends little alpha at early looks, and most of it at the last look.
+func OBrienFlemingSpending(alpha, fraction float64) float64 {
+	z := distuv.UnitNormal.Quantile(1 - alpha/2)
+	return 2 - 2*distuv.UnitNormal.CDF(z/math.Sqrt(fraction))
+}
+
+// PocockSpending is the Lan-DeMets spending function of the Pocock boundary, alpha*log(1+(e-1)*fraction), which spends alpha about evenly across looks.
+func PocockSpending(alpha, fraction float64) float64 {
+	return alpha * math.Log(1+(math.E-1)*fraction)
+}
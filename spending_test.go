@@ -0,0 +1,111 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestSpendingSchedule(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	push := func(st *SequentialTest) *SequentialTest {
+		for _, d := range data {
+			group := 2
+			if d.factor == adultHarmsBaby {
+				group = 1
+			}
+			st.Push(group, float64(d.variable))
+		}
+		return st
+	}
+	run := func(opt SequentialTestOptions) *SequentialTest {
+		return push(NewSequentialTest(NewMom(0.5176537), 0.05, opt))
+	}
+
+	plain := run(SequentialTestOptions{})
+	if plain.StopT() != 30 {
+		t.Fatalf("unexpected stopping time of the plain e-value test: got %d want %d", plain.StopT(), 30)
+	}
+	looks := []int{20, 40, 60, 80, 100, 120}
+	tests := []struct {
+		spending func(alpha, fraction float64) float64
+		stopT    int
+	}{
+		{spending: nil, stopT: 80},
+		{spending: OBrienFlemingSpending, stopT: 80},
+		{spending: PocockSpending, stopT: 80},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			st := run(SequentialTestOptions{SpendingSchedule: SpendingSchedule{Looks: looks, Spending: test.spending}})
+			// The spending schedule rejects only at a look, and no earlier than the plain e-value test.
+			if st.StopT() != test.stopT {
+				t.Errorf("unexpected stopping time: got %d want %d", st.StopT(), test.stopT)
+			}
+			if !slices.Contains(looks, st.StopT()) || st.StopT() < plain.StopT() {
+				t.Errorf("stopping time %d is not a look after %d", st.StopT(), plain.StopT())
+			}
+		})
+	}
+
+	// Without any looks left, the test never rejects.
+	if st := run(SequentialTestOptions{SpendingSchedule: SpendingSchedule{Looks: []int{10, 20}}}); st.Stopped() {
+		t.Errorf("rejected after the last look at %d", st.StopT())
+	}
+
+	// Looks are sorted, and duplicate and non-positive looks are ignored.
+	if st := run(SequentialTestOptions{SpendingSchedule: SpendingSchedule{Looks: []int{120, 40, 0, 20, 80, 40, -5, 100, 60}}}); st.StopT() != tests[0].stopT {
+		t.Errorf("unexpected stopping time with unsorted looks: got %d want %d", st.StopT(), tests[0].stopT)
+	}
+	// Without positive looks, the test is the plain e-value test.
+	if st := run(SequentialTestOptions{SpendingSchedule: SpendingSchedule{Looks: []int{0, -1}}}); st.StopT() != plain.StopT() {
+		t.Errorf("unexpected stopping time without positive looks: got %d want %d", st.StopT(), plain.StopT())
+	}
+	// The test does not retain the looks of the caller.
+	caller := slices.Clone(looks)
+	st := NewSequentialTest(NewMom(0.5176537), 0.05, SequentialTestOptions{SpendingSchedule: SpendingSchedule{Looks: caller}})
+	for i := range caller {
+		caller[i] = 1
+	}
+	if st := push(st); st.StopT() != tests[0].stopT {
+		t.Errorf("unexpected stopping time after modifying the looks: got %d want %d", st.StopT(), tests[0].stopT)
+	}
+}
+
+func TestSpendingScheduleThresholds(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	tests := []struct {
+		spending func(alpha, fraction float64) float64
+		first    float64
+	}{
+		// The O'Brien-Fleming boundary spends 2-2Φ(1.959964/sqrt(0.25)) at a quarter of the information.
+		{spending: OBrienFlemingSpending, first: 8.857e-5},
+		{spending: PocockSpending, first: alpha * math.Log(1+(math.E-1)/4)},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			thresholds := SpendingSchedule{Looks: []int{25, 50, 75, 100}, Spending: test.spending}.thresholds(alpha)
+			if first := 1 / thresholds[0]; !scalar.EqualWithinRel(first, test.first, 1e-3) {
+				t.Errorf("unexpected alpha spent at the first look: got %g want %g", first, test.first)
+			}
+			// The alphas spent at the looks add up to alpha, so each threshold exceeds 1/alpha.
+			var spent float64
+			for _, threshold := range thresholds {
+				if !(threshold > 1/alpha) {
+					t.Errorf("threshold %f does not exceed %f", threshold, 1/alpha)
+				}
+				spent += 1 / threshold
+			}
+			if !scalar.EqualWithinRel(spent, alpha, 1e-12) {
+				t.Errorf("unexpected total alpha spent: got %f want %f", spent, alpha)
+			}
+		})
+	}
+}
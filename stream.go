@@ -0,0 +1,224 @@
+package evalue
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A MomStream computes the e-value of a mom e-process incrementally, as observations of the two groups arrive.
+type MomStream struct {
+	p *Mom
+
+	// n is the number of observations of each group.
+	n [2]int
+	// mean is the mean of the observations of each group.
+	mean [2]float64
+	// m2 is the sum of squared deviations from the mean of each group.
+	// Updating the mean and m2 by Welford's algorithm avoids the catastrophic cancellation of running sums of squares, when the mean is large relative to the spread.
+	m2 [2]float64
+}
+
+// NewMomStream creates a stream for the mom e-process p.
+func NewMomStream(p *Mom) *MomStream {
+	return &MomStream{p: p}
+}
+
+// Push adds the observation v to a group, which must be either 1 or 2.
+func (s *MomStream) Push(group int, v float64) {
+	i := group - 1
+	s.n[i]++
+	d := v - s.mean[i]
+	s.mean[i] += d / float64(s.n[i])
+	s.m2[i] += d * (v - s.mean[i])
+}
+
+// PushGroup1 adds the observation v to group 1.
+// The groups need not arrive in lockstep, and the e-value is that of the current, possibly unbalanced, group sizes.
+func (s *MomStream) PushGroup1(v float64) {
+	s.Push(1, v)
+}
+
+// PushGroup2 adds the observation v to group 2, see PushGroup1.
+func (s *MomStream) PushGroup2(v float64) {
+	s.Push(2, v)
+}
+
+// Merge adds the observations of other to s.
+// The e-value of the merged stream equals the e-value of the concatenated data.
+// The means and squared deviations are combined by the parallel formula of Chan et al.
+func (s *MomStream) Merge(other *MomStream) {
+	for i := range s.n {
+		n := s.n[i] + other.n[i]
+		if n == 0 {
+			continue
+		}
+		na, nb := float64(s.n[i]), float64(other.n[i])
+		d := other.mean[i] - s.mean[i]
+		s.mean[i] += d * nb / float64(n)
+		s.m2[i] += other.m2[i] + d*d*na*nb/float64(n)
+		s.n[i] = n
+	}
+}
+
+// Restart discards all observations, while keeping the mom e-process.
+// Each restart begins a fresh anytime-valid test, whose e-value carries no evidence from before the restart.
+// Note that the type I error guarantee holds for each test separately, and so restarting many times, for example after each detected change, inflates the overall type I error.
+func (s *MomStream) Restart() {
+	s.n = [2]int{}
+	s.mean = [2]float64{}
+	s.m2 = [2]float64{}
+}
+
+// TStat returns the two sample t-statistic of the observations so far.
+func (s *MomStream) TStat() TStatistic {
+	n1, n2 := float64(s.n[0]), float64(s.n[1])
+	nu := n1 + n2 - 2
+	nEff := EffectiveSampleSize(s.n[0], s.n[1])
+	mean1, mean2 := s.groupMean(0), s.groupMean(1)
+
+	sp := math.Sqrt(1. / nu * (s.m2[0] + s.m2[1]))
+	t := math.Sqrt(nEff) * (mean1 - mean2) / sp
+
+	ts := TStatistic{
+		Nu:    nu,
+		NEff:  nEff,
+		Mean1: mean1,
+		Mean2: mean2,
+		Sp:    sp,
+		T:     t,
+	}
+	return ts
+}
+
+// EValue returns the e-value of the observations so far.
+// It returns 1 until there are enough observations to estimate the variance, and while the pooled variance is zero.
+func (s *MomStream) EValue() float64 {
+	if s.n[0] == 0 || s.n[1] == 0 || s.n[0]+s.n[1] <= 2 {
+		return 1
+	}
+	if !s.positiveVariance(0) && !s.positiveVariance(1) {
+		return 1
+	}
+	t := s.TStat()
+	return s.p.eValue(t.T, t.Nu, t.NEff)
+}
+
+// groupMean returns the mean of the observations of group i, which is NaN for an empty group.
+func (s *MomStream) groupMean(i int) float64 {
+	if s.n[i] == 0 {
+		return math.NaN()
+	}
+	return s.mean[i]
+}
+
+// positiveVariance reports whether the observations of group i have a positive sample variance.
+// Welford updates keep the squared deviations of identical observations at exactly zero, and those of any differing observations positive, whatever the mean.
+func (s *MomStream) positiveVariance(i int) bool {
+	return s.n[i] >= 2 && s.m2[i] > 0
+}
+
+// StreamTest performs a sequential test on newline-delimited records read from r, such as "1 3.2\n2 4.1\n".
+// Each record consists of a group, which must be either 1 or 2, and an observation, separated by white space or a comma.
+// Blank lines are skipped.
+// The mom e-process is tuned to the minimal effect size deltaMin, and the test stops reading at the first record where the e-value exceeds 1/alpha.
+// It returns the number of observations at the stopping time, or -1 if the null hypothesis is not rejected.
+func StreamTest(r io.Reader, alpha, deltaMin float64) (int, error) {
+	if !validProbability(alpha) {
+		return notStopped, fmt.Errorf("%w: alpha %f", ErrInvalidProbability, alpha)
+	}
+	s := NewMomStream(NewMom(deltaMin))
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		d, ok, err := parseRecord(scanner.Text(), line)
+		if err != nil {
+			return notStopped, err
+		}
+		if !ok {
+			continue
+		}
+
+		s.Push(d.Group, d.Value)
+		if s.EValue() > 1./alpha {
+			return s.n[0] + s.n[1], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return notStopped, fmt.Errorf("evalue: %w", err)
+	}
+	return notStopped, nil
+}
+
+// parseRecord parses a record of StreamTest at the given line number, and reports whether the line is a record rather than blank.
+func parseRecord(text string, line int) (LabeledDatum, bool, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+	if len(fields) == 0 {
+		return LabeledDatum{}, false, nil
+	}
+	if len(fields) != 2 {
+		return LabeledDatum{}, false, fmt.Errorf("evalue: line %d: want 2 fields got %d", line, len(fields))
+	}
+	group, err := strconv.Atoi(fields[0])
+	if err != nil || (group != 1 && group != 2) {
+		return LabeledDatum{}, false, fmt.Errorf("evalue: line %d: invalid group %q", line, fields[0])
+	}
+	v, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return LabeledDatum{}, false, fmt.Errorf("evalue: line %d: %w", line, err)
+	}
+	return LabeledDatum{Group: group, Value: v}, true, nil
+}
+
+// RunFromReader performs a sequential test on the records read from r, which are in the format of StreamTest, and writes a human-readable verdict to w.
+// The verdict consists of the stopping time, the e-value at the stopping time, and the confidence interval of the difference between the group means at the stopping time.
+// If the null hypothesis is not rejected, the verdict is that of all records.
+func RunFromReader(r io.Reader, alpha, deltaMin float64, w io.Writer) error {
+	if !validProbability(alpha) {
+		return fmt.Errorf("%w: alpha %f", ErrInvalidProbability, alpha)
+	}
+	var data []LabeledDatum
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		d, ok, err := parseRecord(scanner.Text(), line)
+		if err != nil {
+			return err
+		}
+		if ok {
+			data = append(data, d)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("evalue: %w", err)
+	}
+
+	res := RunSequential(data, alpha, deltaMin)
+	n := len(res.EValue)
+	eValue := 1.
+	if n > 0 {
+		eValue = res.EValue[n-1]
+	}
+	var x, y []float64
+	for _, d := range data[:n] {
+		if d.Group == 1 {
+			x = append(x, d.Value)
+		} else {
+			y = append(y, d.Value)
+		}
+	}
+	ci := NewMom(deltaMin).CI(x, y, alpha)
+
+	var b strings.Builder
+	if res.StopT != notStopped {
+		fmt.Fprintf(&b, "Null hypothesis rejected at stopping time %d of %d observations.\n", res.StopT, len(data))
+	} else {
+		fmt.Fprintf(&b, "Null hypothesis not rejected after %d observations.\n", len(data))
+	}
+	fmt.Fprintf(&b, "E-value: %g\n", eValue)
+	fmt.Fprintf(&b, "%g%% confidence interval of the mean difference: [%g, %g]\n", 100*(1-alpha), ci[0], ci[1])
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,260 @@
+package evalue
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestMomStreamMerge(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	p := &Mom{G: 0.1339827}
+
+	// Collect the data at two sites.
+	site1, site2 := NewMomStream(p), NewMomStream(p)
+	for i, d := range data {
+		site := site1
+		if i >= 50 {
+			site = site2
+		}
+		group := 2
+		if d.factor == adultHarmsBaby {
+			group = 1
+		}
+		site.Push(group, float64(d.variable))
+	}
+
+	site1.Merge(site2)
+	x, y := splitGray(data)
+	if e, want := site1.EValue(), p.EValue(x, y); !scalar.EqualWithinRel(e, want, 1e-9) {
+		t.Errorf("unexpected merged e-value: got %f want %f", e, want)
+	}
+}
+
+func TestMomStreamLargeOffset(t *testing.T) {
+	t.Parallel()
+	p := &Mom{G: 0.1339827}
+	for i, offset := range []float64{0, 1e6, 1e8} {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			rnd := rand.New(rand.NewPCG(uint64(i), 1))
+			x, y := GaussianGen{Delta: 0.5}.Generate(rnd, 60)
+			for j := range x {
+				x[j] += offset
+				y[j] += offset
+			}
+
+			// Collect the data at two sites, and merge them.
+			s, site2 := NewMomStream(p), NewMomStream(p)
+			for j := range x {
+				site := s
+				if j >= 25 {
+					site = site2
+				}
+				site.Push(1, x[j])
+				site.Push(2, y[j])
+			}
+			s.Merge(site2)
+
+			ts, want := s.TStat(), TStat(x, y, 0)
+			if !scalar.EqualWithinRel(ts.T, want.T, 1e-6) || !scalar.EqualWithinRel(ts.Sp, want.Sp, 1e-6) {
+				t.Errorf("unexpected t-statistic: got %f %f want %f %f", ts.T, ts.Sp, want.T, want.Sp)
+			}
+			if e, want := s.EValue(), p.EValue(x, y); !scalar.EqualWithinRel(e, want, 1e-5) {
+				t.Errorf("unexpected e-value: got %f want %f", e, want)
+			}
+		})
+	}
+}
+
+func TestMomStreamRestart(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	p := &Mom{G: 0.1339827}
+	push := func(s *MomStream, data []grayCase) {
+		for _, d := range data {
+			group := 2
+			if d.factor == adultHarmsBaby {
+				group = 1
+			}
+			s.Push(group, float64(d.variable))
+		}
+	}
+
+	restarted := NewMomStream(p)
+	push(restarted, data[:50])
+	restarted.Restart()
+	if e := restarted.EValue(); e != 1 {
+		t.Errorf("unexpected e-value after restart: got %f want 1", e)
+	}
+	fresh := NewMomStream(p)
+	for i := 50; i < len(data); i++ {
+		push(restarted, data[i:i+1])
+		push(fresh, data[i:i+1])
+		if restarted.EValue() != fresh.EValue() {
+			t.Fatalf("unexpected e-value at %d: got %f want %f", i, restarted.EValue(), fresh.EValue())
+		}
+	}
+}
+
+func TestMomStreamUnevenArrivals(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data)
+	p := &Mom{G: 0.1339827}
+
+	// Group 1 arrives in bursts of three, while group 2 trickles in one at a time.
+	s := NewMomStream(p)
+	var i, j int
+	for i < len(x) || j < len(y) {
+		for range 3 {
+			if i < len(x) {
+				s.PushGroup1(x[i])
+				i++
+			}
+		}
+		if j < len(y) {
+			s.PushGroup2(y[j])
+			j++
+		}
+		if i < 2 || j < 2 {
+			continue
+		}
+		if e, want := s.EValue(), p.EValue(x[:i], y[:j]); !scalar.EqualWithinRel(e, want, 1e-9) {
+			t.Errorf("%d %d: got %f want %f", i, j, e, want)
+		}
+	}
+}
+
+func TestMomStreamZeroVariance(t *testing.T) {
+	t.Parallel()
+	s := NewMomStream(&Mom{G: 0.1339827})
+	for range 5 {
+		s.Push(1, 0.1)
+		s.Push(2, 0.7)
+		if e := s.EValue(); e != 1 {
+			t.Fatalf("unexpected e-value of constant groups: got %f want 1", e)
+		}
+	}
+}
+
+func TestMomStreamZeroVarianceLargeOffset(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	const offset = 1e7
+	st := NewSequentialTest(NewMom(0.5176537), 0.05, SequentialTestOptions{RequirePositiveVariance: true})
+	for _, d := range data {
+		group := 2
+		if d.factor == adultHarmsBaby {
+			group = 1
+		}
+		st.Push(group, offset+float64(d.variable))
+	}
+	// A large mean does not hide the variance of the data.
+	if st.StopT() != 30 {
+		t.Errorf("unexpected stopping time: got %d want %d", st.StopT(), 30)
+	}
+
+	s := NewMomStream(&Mom{G: 0.1339827})
+	for range 5 {
+		s.Push(1, offset+0.1)
+		s.Push(2, offset+0.7)
+		if e := s.EValue(); e != 1 {
+			t.Fatalf("unexpected e-value of constant groups: got %f want 1", e)
+		}
+	}
+}
+
+func TestStreamTest(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	var b strings.Builder
+	for _, d := range data {
+		group := 2
+		if d.factor == adultHarmsBaby {
+			group = 1
+		}
+		fmt.Fprintf(&b, "%d %d\n", group, d.variable)
+	}
+
+	stopT, err := StreamTest(strings.NewReader(b.String()), 0.05, 0.5176537)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if stopT != 30 {
+		t.Errorf("unexpected stopping time: got %d want %d", stopT, 30)
+	}
+}
+
+func TestStreamTestErr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input string
+		alpha float64
+		stopT int
+		err   bool
+	}{
+		{input: "1 3.2\n2 4.1\n\n1 2.5\n", alpha: 0.05, stopT: -1},
+		{input: "1 3.2\n3 4.1\n", alpha: 0.05, stopT: -1, err: true},
+		{input: "1 3.2\n2\n", alpha: 0.05, stopT: -1, err: true},
+		{input: "1 3.2\n2 abc\n", alpha: 0.05, stopT: -1, err: true},
+		{input: "1 3.2\n", alpha: 1, stopT: -1, err: true},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			stopT, err := StreamTest(strings.NewReader(test.input), test.alpha, 0.5)
+			if (err != nil) != test.err {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if stopT != test.stopT {
+				t.Errorf("unexpected stopping time: got %d want %d", stopT, test.stopT)
+			}
+		})
+	}
+	if _, err := StreamTest(strings.NewReader(""), 0, 0.5); !errors.Is(err, ErrInvalidProbability) {
+		t.Errorf("unexpected error: got %v want %v", err, ErrInvalidProbability)
+	}
+}
+
+func TestRunFromReader(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	var b strings.Builder
+	for _, d := range data {
+		group := 2
+		if d.factor == adultHarmsBaby {
+			group = 1
+		}
+		fmt.Fprintf(&b, "%d,%d\n", group, d.variable)
+	}
+
+	var out strings.Builder
+	if err := RunFromReader(strings.NewReader(b.String()), 0.05, 0.5176537, &out); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if want := "Null hypothesis rejected at stopping time 30 of 121 observations."; lines[0] != want {
+		t.Errorf("got %q want %q", lines[0], want)
+	}
+	if !strings.HasPrefix(lines[1], "E-value: 2") || !strings.HasPrefix(lines[2], "95% confidence interval of the mean difference: [") {
+		t.Errorf("unexpected verdict %q", out.String())
+	}
+
+	out.Reset()
+	if err := RunFromReader(strings.NewReader("1 3.2\n2 4.1\n"), 0.05, 0.5, &out); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if want := "Null hypothesis not rejected after 2 observations.\nE-value: 1\n95% confidence interval of the mean difference: [-Inf, +Inf]\n"; out.String() != want {
+		t.Errorf("got %q want %q", out.String(), want)
+	}
+	if err := RunFromReader(strings.NewReader("1 3.2\n3 4.1\n"), 0.05, 0.5, &out); err == nil {
+		t.Errorf("invalid group is not reported")
+	}
+}
@@ -0,0 +1,130 @@
+package evalue
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// Columns of a TrajectoryWriter.
+const (
+	// ColumnSample is the index of the trajectory.
+	ColumnSample = "sample"
+	// ColumnStep is the index of the observation within the trajectory.
+	ColumnStep = "t"
+	// ColumnX is the observation of the first group.
+	ColumnX = "x"
+	// ColumnY is the observation of the second group.
+	ColumnY = "y"
+	// ColumnPValue is the p-value.
+	ColumnPValue = "p"
+	// ColumnEValue is the e-value.
+	ColumnEValue = "e"
+)
+
+// allColumns are the columns of a TrajectoryWriter in their default order.
+var allColumns = []string{ColumnSample, ColumnStep, ColumnX, ColumnY, ColumnPValue, ColumnEValue}
+
+// A Trajectory is the evolution of a two sample experiment, in which the i-th elements of its fields are those after the i-th observation of each group.
+type Trajectory struct {
+	X      []float64
+	Y      []float64
+	PValue []float64
+	EValue []float64
+}
+
+// TrajectoryWriterOptions are options for NewTrajectoryWriter.
+type TrajectoryWriterOptions struct {
+	// Format is the format of floats, see strconv.FormatFloat, and defaults to 'f'.
+	Format byte
+	// Precision is the precision of floats, see strconv.FormatFloat.
+	// It defaults to -1, which is the smallest precision that parses back to the exact float.
+	// Since zero takes the default, a precision of zero digits cannot be requested, and a zero Precision is written like -1.
+	Precision int
+	// Columns are the columns to write in order, and default to all columns.
+	Columns []string
+}
+
+// A TrajectoryWriter writes trajectories in CSV format, one row per observation, for plotting pipelines such as plot.py.
+type TrajectoryWriter struct {
+	cw  *csv.Writer
+	opt TrajectoryWriterOptions
+	// headerWritten is whether the header row is written.
+	headerWritten bool
+}
+
+// NewTrajectoryWriter creates a writer of trajectories to w.
+// It returns an error wrapping ErrInvalidOptions if a column is unknown.
+func NewTrajectoryWriter(w io.Writer, options ...TrajectoryWriterOptions) (*TrajectoryWriter, error) {
+	var opt TrajectoryWriterOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Format == 0 {
+		opt.Format = 'f'
+	}
+	if opt.Precision == 0 {
+		opt.Precision = -1
+	}
+	if len(opt.Columns) == 0 {
+		opt.Columns = allColumns
+	}
+	for _, c := range opt.Columns {
+		if !slices.Contains(allColumns, c) {
+			return nil, fmt.Errorf("%w: column %q", ErrInvalidOptions, c)
+		}
+	}
+
+	tw := &TrajectoryWriter{cw: csv.NewWriter(w), opt: opt}
+	return tw, nil
+}
+
+// Write writes the trajectory tr with the index sample.
+// The fields of tr that are written must have the same length.
+func (tw *TrajectoryWriter) Write(sample int, tr Trajectory) error {
+	if !tw.headerWritten {
+		if err := tw.cw.Write(tw.opt.Columns); err != nil {
+			return err
+		}
+		tw.headerWritten = true
+	}
+
+	series := map[string][]float64{ColumnX: tr.X, ColumnY: tr.Y, ColumnPValue: tr.PValue, ColumnEValue: tr.EValue}
+	n := -1
+	for _, c := range tw.opt.Columns {
+		s, ok := series[c]
+		if !ok {
+			continue
+		}
+		if n != -1 && len(s) != n {
+			return fmt.Errorf("evalue: column %q has length %d want %d", c, len(s), n)
+		}
+		n = len(s)
+	}
+
+	row := make([]string, len(tw.opt.Columns))
+	for i := range max(n, 0) {
+		for j, c := range tw.opt.Columns {
+			switch c {
+			case ColumnSample:
+				row[j] = strconv.Itoa(sample)
+			case ColumnStep:
+				row[j] = strconv.Itoa(i)
+			default:
+				row[j] = strconv.FormatFloat(series[c][i], tw.opt.Format, tw.opt.Precision, 64)
+			}
+		}
+		if err := tw.cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (tw *TrajectoryWriter) Flush() error {
+	tw.cw.Flush()
+	return tw.cw.Error()
+}
@@ -0,0 +1,130 @@
+package evalue
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestTrajectoryWriter(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(newDefaultRandSource())
+	p := NewMom(0.5)
+	var trajectories []Trajectory
+	for range 3 {
+		x, y := GaussianGen{Delta: 0.5}.Generate(rnd, 20)
+		var tr Trajectory
+		for i := range x {
+			tr.X, tr.Y = append(tr.X, x[i]), append(tr.Y, y[i])
+			pValue := 1.
+			if i > 0 {
+				pValue = PValueStudent(TStat(x[:i+1], y[:i+1], 0))
+			}
+			tr.PValue = append(tr.PValue, pValue)
+			tr.EValue = append(tr.EValue, p.EValue(x[:i+1], y[:i+1]))
+		}
+		trajectories = append(trajectories, tr)
+	}
+
+	tests := []struct {
+		options TrajectoryWriterOptions
+		header  []string
+		tol     float64
+	}{
+		{header: allColumns},
+		{options: TrajectoryWriterOptions{Format: 'e', Precision: 3, Columns: []string{ColumnEValue, ColumnSample}}, header: []string{"e", "sample"}, tol: 1e-3},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			buf := bytes.NewBuffer(nil)
+			tw, err := NewTrajectoryWriter(buf, test.options)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			for s, tr := range trajectories {
+				if err := tw.Write(s, tr); err != nil {
+					t.Fatalf("%+v", err)
+				}
+			}
+			if err := tw.Flush(); err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			// Parse the trajectories back.
+			rows, err := csv.NewReader(buf).ReadAll()
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if !slices.Equal(rows[0], test.header) {
+				t.Fatalf("unexpected header: got %v want %v", rows[0], test.header)
+			}
+			if len(rows)-1 != 3*20 {
+				t.Fatalf("unexpected number of rows %d", len(rows)-1)
+			}
+			for k, row := range rows[1:] {
+				s, step := k/20, k%20
+				tr := trajectories[s]
+				want := map[string]float64{ColumnSample: float64(s), ColumnStep: float64(step), ColumnX: tr.X[step], ColumnY: tr.Y[step], ColumnPValue: tr.PValue[step], ColumnEValue: tr.EValue[step]}
+				for j, c := range test.header {
+					v, err := strconv.ParseFloat(row[j], 64)
+					if err != nil {
+						t.Fatalf("%+v", err)
+					}
+					if !(v == want[c] || scalar.EqualWithinRel(v, want[c], test.tol)) {
+						t.Errorf("row %d column %s: got %f want %f", k, c, v, want[c])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTrajectoryWriterZeroPrecision(t *testing.T) {
+	t.Parallel()
+	tr := Trajectory{X: []float64{0.25}, Y: []float64{1}, PValue: []float64{1}, EValue: []float64{1.5}}
+	write := func(precision int) string {
+		buf := bytes.NewBuffer(nil)
+		tw, err := NewTrajectoryWriter(buf, TrajectoryWriterOptions{Precision: precision})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if err := tw.Write(0, tr); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if err := tw.Flush(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return buf.String()
+	}
+
+	// A zero Precision takes the default -1, rather than rounding to integers.
+	zero, want := write(0), "sample,t,x,y,p,e\n0,0,0.25,1,1,1.5\n"
+	if zero != want {
+		t.Errorf("unexpected output of zero precision: got %q want %q", zero, want)
+	}
+	if shortest := write(-1); zero != shortest {
+		t.Errorf("zero precision %q differs from -1 %q", zero, shortest)
+	}
+}
+
+func TestTrajectoryWriterErr(t *testing.T) {
+	t.Parallel()
+	if _, err := NewTrajectoryWriter(bytes.NewBuffer(nil), TrajectoryWriterOptions{Columns: []string{"q"}}); !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("unexpected error %+v", err)
+	}
+	tw, err := NewTrajectoryWriter(bytes.NewBuffer(nil))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := tw.Write(0, Trajectory{X: []float64{1, 2}, Y: []float64{1}}); err == nil {
+		t.Errorf("mismatched lengths are not reported")
+	}
+}
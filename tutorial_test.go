@@ -3,13 +3,10 @@ package evalue
 import (
 	"bytes"
 	"encoding/csv"
-	"math"
 	"math/rand/v2"
 	"os"
 	"strconv"
 	"testing"
-
-	"gonum.org/v1/gonum/stat/distuv"
 )
 
 // TestOptionalContinuation tests that e-values support optional continuation.
@@ -33,10 +30,7 @@ func TestOptionalContinuation(t *testing.T) {
 		eValue []float64
 	}
 	getPValue := func(x, y []float64) float64 {
-		ts := TStat(x, y, 0)
-		dist := distuv.StudentsT{Sigma: 1, Nu: ts.Nu, Src: rsrc}
-		pValue := 2 * (1 - dist.CDF(math.Abs(ts.T)))
-		return pValue
+		return PValueStudent(TStat(x, y, 0))
 	}
 	getEValue := func(x, y []float64) float64 {
 		p := NewMom(0.51765)
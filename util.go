@@ -1,10 +1,22 @@
 package evalue
 
-import "math"
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/exp/root"
+)
+
+var (
+	errNotMonotone = errors.New("evalue: function is not monotonically increasing")
+	errNoBracket   = errors.New("evalue: bracket not found")
+)
 
 // findBracketMono finds a bracket interval [a, b] where f(a)f(b) < 0.
 // f must be a monotonically increasing function.
-func findBracketMono(f func(float64) float64, guess float64) (float64, float64) {
+// An error is returned if f is found to be decreasing between two sampled points, or if no bracket is found.
+func findBracketMono(f func(float64) float64, guess float64) (float64, float64, error) {
 	// Make sure initial guess has the same sign as the root.
 	f0 := f(0)
 	if (guess < 0 && f0 < 0) || (guess > 0 && f0 > 0) {
@@ -23,13 +35,54 @@ func findBracketMono(f func(float64) float64, guess float64) (float64, float64)
 	b := a * r
 	fb := f(b)
 	for range 200 {
+		if (b-a)*(fb-fa) < 0 {
+			return 0, 0, errNotMonotone
+		}
 		if math.Signbit(fa) != math.Signbit(fb) || fa == 0 || fb == 0 {
-			break
+			return a, b, nil
 		}
 		a, fa = b, fb
 		b *= r
 		fb = f(b)
 	}
 
-	return a, b
+	return 0, 0, errNoBracket
+}
+
+// solveBrentMono returns the root of the monotonically increasing function f.
+// The root is bracketed starting from guess with findBracketMono, and then refined with Brent's method to the tolerance tol.
+// An error is returned if either step fails.
+func solveBrentMono(f func(float64) float64, guess, tol float64) (float64, error) {
+	a, b, err := findBracketMono(f, guess)
+	if err != nil {
+		return 0, err
+	}
+	x, err := root.Brent(f, a, b, tol)
+	if err != nil {
+		return 0, fmt.Errorf("evalue: %w", err)
+	}
+	return x, nil
+}
+
+// maximizeGolden returns the maximizer of f in [a, b] with golden section search.
+// f must be unimodal in [a, b].
+func maximizeGolden(f func(float64) float64, a, b float64) float64 {
+	invPhi := (math.Sqrt(5) - 1) / 2
+	c, d := b-invPhi*(b-a), a+invPhi*(b-a)
+	fc, fd := f(c), f(d)
+	for range 200 {
+		if b-a <= 1e-10*(math.Abs(a)+math.Abs(b)) {
+			break
+		}
+		if fc > fd {
+			b, d, fd = d, c, fc
+			c = b - invPhi*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + invPhi*(b-a)
+			fd = f(d)
+		}
+	}
+	return (a + b) / 2
 }
@@ -0,0 +1,93 @@
+package evalue
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestFindBracketMono(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		f     func(float64) float64
+		guess float64
+		err   error
+	}{
+		{f: func(x float64) float64 { return x - 100 }, guess: 1},
+		{f: func(x float64) float64 { return x + 0.01 }, guess: 1},
+		{f: func(x float64) float64 { return math.Atan(x - 1) }, guess: -3},
+		// Decreasing.
+		{f: func(x float64) float64 { return 100 - x }, guess: 1, err: errNotMonotone},
+		// Decreasing for x > 1.
+		{f: func(x float64) float64 { return 2*x*math.Exp(1-x*x) - 3 }, guess: 1, err: errNotMonotone},
+		// Increasing without a root.
+		{f: func(x float64) float64 { return math.Exp(x) + 1 }, guess: 1, err: errNoBracket},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			a, b, err := findBracketMono(test.f, test.guess)
+			if err != test.err {
+				t.Fatalf("unexpected error: got %v want %v", err, test.err)
+			}
+			if err != nil {
+				return
+			}
+			if fa, fb := test.f(a), test.f(b); math.Signbit(fa) == math.Signbit(fb) && fa != 0 && fb != 0 {
+				t.Errorf("invalid bracket [%f, %f] with f values %f %f", a, b, fa, fb)
+			}
+		})
+	}
+}
+
+func TestSolveBrentMono(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		f     func(float64) float64
+		guess float64
+		want  float64
+		err   error
+	}{
+		{f: func(x float64) float64 { return x - 100 }, guess: 1, want: 100},
+		{f: func(x float64) float64 { return math.Atan(x - 1) }, guess: -3, want: 1},
+		{f: func(x float64) float64 { return x*x*x + 8 }, guess: 5, want: -2},
+		{f: func(x float64) float64 { return 100 - x }, guess: 1, err: errNotMonotone},
+		{f: func(x float64) float64 { return math.Exp(x) + 1 }, guess: 1, err: errNoBracket},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			x, err := solveBrentMono(test.f, test.guess, 1e-12)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("unexpected error: got %v want %v", err, test.err)
+			}
+			if err == nil && !scalar.EqualWithinAbs(x, test.want, 1e-9) {
+				t.Errorf("got %f want %f", x, test.want)
+			}
+		})
+	}
+}
+
+func TestMaximizeGolden(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		f    func(float64) float64
+		a, b float64
+		want float64
+	}{
+		{f: func(x float64) float64 { return -(x - 1) * (x - 1) }, a: -3, b: 5, want: 1},
+		{f: func(x float64) float64 { return math.Log(x) - x }, a: 0, b: 10, want: 1},
+		{f: func(x float64) float64 { return x }, a: -2, b: 3, want: 3},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if x := maximizeGolden(test.f, test.a, test.b); !scalar.EqualWithinAbs(x, test.want, 1e-6) {
+				t.Errorf("got %f want %f", x, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package evalue
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// varRatioPriorScale is the standard deviation of the normal prior on the log variance ratio in EValueVarRatio.
+// It puts most of the prior mass on variance ratios between 1/4 and 4.
+var varRatioPriorScale = math.Log(2)
+
+// EValueVarRatio returns the e-value for the null hypothesis that the ratio between the variances of the two groups is ratio0, which is the anytime-valid analogue of the F-test.
+// Under the null hypothesis, the ratio between the sample variances divided by ratio0 follows the F-distribution.
+// The e-value is the Bayes factor of this ratio, with a normal prior on the log of the true variance ratio relative to ratio0 under the alternative.
+// Since the ratio between the sample variances is invariant to the location and scale of the data, the e-value is an e-process like EValue.
+//
+// It is 1 if either group has fewer than two observations, and NaN if either sample variance is zero or undefined.
+// Like the F-test, it assumes Gaussian data, and is sensitive to heavy tails.
+func EValueVarRatio(x, y []float64, ratio0 float64) float64 {
+	if len(x) < 2 || len(y) < 2 {
+		return 1
+	}
+	f := stat.Variance(x, nil) / stat.Variance(y, nil) / ratio0
+	if !(f > 0) || math.IsInf(f, 1) {
+		return math.NaN()
+	}
+
+	dist := distuv.F{D1: float64(len(x) - 1), D2: float64(len(y) - 1)}
+	logNull := dist.LogProb(f)
+	prior := distuv.Normal{Sigma: varRatioPriorScale}
+	// Integrate the likelihood ratio over the log variance ratio u, under which f*exp(-u) follows the F-distribution.
+	integrand := func(u float64) float64 {
+		return math.Exp(dist.LogProb(f*math.Exp(-u))-u-logNull) * prior.Prob(u)
+	}
+	const numSigma = 8
+	return quad.Fixed(integrand, -numSigma*varRatioPriorScale, numSigma*varRatioPriorScale, 128, nil, 0)
+}
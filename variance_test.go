@@ -0,0 +1,87 @@
+package evalue
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestEValueVarRatio(t *testing.T) {
+	t.Parallel()
+	const alpha = 0.05
+	const numSims = 200
+	const n = 100
+	tests := []struct {
+		ratio float64
+		// rejectRate is the fraction of simulations whose e-value ever exceeds 1/alpha.
+		rejectRate float64
+	}{
+		{ratio: 1, rejectRate: 0.01},
+		{ratio: 2, rejectRate: 0.705},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			rnd := rand.New(newDefaultRandSource())
+			var rejected int
+			var logE float64
+			for range numSims {
+				x, y := GaussianGen{}.Generate(rnd, n)
+				for i := range x {
+					x[i] *= math.Sqrt(test.ratio)
+				}
+				for m := 2; m <= n; m++ {
+					if EValueVarRatio(x[:m], y[:m], 1) > 1./alpha {
+						rejected++
+						break
+					}
+				}
+				logE += math.Log(EValueVarRatio(x, y, 1))
+			}
+			rate := float64(rejected) / numSims
+			if rate != test.rejectRate {
+				t.Errorf("got reject rate %f want %f", rate, test.rejectRate)
+			}
+			if test.ratio == 1 && !(rate <= alpha) {
+				t.Errorf("Type I error %f exceeds %f", rate, alpha)
+			}
+			// Evidence grows under the alternative, and shrinks under the null hypothesis.
+			if meanLogE := logE / numSims; (meanLogE > 0) != (test.ratio != 1) {
+				t.Errorf("unexpected mean log e-value %f", meanLogE)
+			}
+		})
+	}
+}
+
+func TestEValueVarRatioInvariance(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(newDefaultRandSource())
+	x, y := GaussianGen{}.Generate(rnd, 30)
+	e := EValueVarRatio(x, y, 1)
+
+	// The e-value is invariant to the location and scale of the data.
+	x2, y2 := make([]float64, len(x)), make([]float64, len(y))
+	for i := range x {
+		x2[i], y2[i] = 3*x[i]+5, 3*y[i]-2
+	}
+	if e2 := EValueVarRatio(x2, y2, 1); !scalar.EqualWithinRel(e, e2, 1e-9) {
+		t.Errorf("got %f want %f", e2, e)
+	}
+	// Scaling one group is equivalent to scaling ratio0.
+	for i := range x {
+		x2[i] = 2 * x[i]
+	}
+	if e2 := EValueVarRatio(x2, y, 4); !scalar.EqualWithinRel(e, e2, 1e-9) {
+		t.Errorf("got %f want %f", e2, e)
+	}
+
+	if e := EValueVarRatio(x[:1], y, 1); e != 1 {
+		t.Errorf("got %f want 1", e)
+	}
+	if e := EValueVarRatio([]float64{1, 1}, y, 1); !math.IsNaN(e) {
+		t.Errorf("got %f want NaN", e)
+	}
+}
@@ -0,0 +1,35 @@
+package evalue
+
+import (
+	"slices"
+)
+
+// TStatWinsor returns the two sample t-statistic of the data, after Winsorizing each group at the fraction frac.
+// Winsorizing replaces the smallest and largest frac of each group by the nearest remaining value, limiting the influence of outliers on Sp.
+func TStatWinsor(x1, x2 []float64, phi0, frac float64) TStatistic {
+	return TStat(winsorize(x1, frac), winsorize(x2, frac), phi0)
+}
+
+// EValueWinsor returns the e-value of the two sample data, after Winsorizing each group at the fraction frac.
+// This trades exact validity for robustness: the e-value is computed as if the Winsorized data were Gaussian, so its Type I error guarantee holds only approximately.
+// Like EValue, it returns 1 if there are too few observations to estimate the variance or both Winsorized groups are constant.
+func (p *Mom) EValueWinsor(x, y []float64, frac float64) float64 {
+	return p.EValueFromTStat(TStatWinsor(x, y, 0, frac))
+}
+
+// winsorize returns a copy of x, with its smallest and largest frac values clamped.
+func winsorize(x []float64, frac float64) []float64 {
+	sorted := slices.Clone(x)
+	slices.Sort(sorted)
+	k := int(frac * float64(len(x)))
+	if k <= 0 || 2*k >= len(x) {
+		return slices.Clone(x)
+	}
+	lo, hi := sorted[k], sorted[len(x)-1-k]
+
+	w := make([]float64, len(x))
+	for i, v := range x {
+		w[i] = min(max(v, lo), hi)
+	}
+	return w
+}
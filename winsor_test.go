@@ -0,0 +1,50 @@
+package evalue
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEValueWinsor(t *testing.T) {
+	t.Parallel()
+	data := grayData[slices.IndexFunc(grayData, func(d []grayCase) bool { return d[0].location == "Carleton University, Ottawa, Canada" })]
+	x, y := splitGray(data[:60])
+	p := &Mom{G: 0.1339827}
+	const frac = 0.05
+
+	clean := p.EValue(x, y)
+	yOutlier := slices.Clone(y)
+	yOutlier[3] = 100
+	outlier := p.EValue(x, yOutlier)
+	winsor := p.EValueWinsor(x, yOutlier, frac)
+
+	dOutlier := math.Abs(math.Log(outlier) - math.Log(clean))
+	dWinsor := math.Abs(math.Log(winsor) - math.Log(clean))
+	if !(dWinsor < dOutlier/2) {
+		t.Errorf("Winsorized e-value %f does not recover towards the clean %f from %f", winsor, clean, outlier)
+	}
+
+	// Winsorizing does not modify the input.
+	if yOutlier[3] != 100 {
+		t.Errorf("input modified")
+	}
+
+	// Degenerate data are handled like EValue.
+	if e := p.EValueWinsor(x[:1], y[:1], frac); e != 1 {
+		t.Errorf("unexpected e-value of single observations: got %f want 1", e)
+	}
+	if e := p.EValueWinsor([]float64{3, 3, 3}, []float64{5, 5, 5}, frac); e != 1 {
+		t.Errorf("unexpected e-value of constant groups: got %f want 1", e)
+	}
+}
+
+func TestWinsorize(t *testing.T) {
+	t.Parallel()
+	x := []float64{5, -100, 3, 1, 2, 4, 50, 6, 7, 8}
+	got := winsorize(x, 0.1)
+	want := []float64{5, 1, 3, 1, 2, 4, 8, 6, 7, 8}
+	if !slices.Equal(got, want) {
+		t.Errorf("winsorize: got %v want %v", got, want)
+	}
+}